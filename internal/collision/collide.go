@@ -0,0 +1,264 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// Manifold describes how two shapes overlap.
+//
+// Normal is a unit vector pointing from the first shape towards the second,
+// and Depth is how far they'd have to move apart along it to only be
+// touching.
+// Contacts are the points where the shapes touch, of which there are two
+// when edges are lying against each other and one otherwise.
+type Manifold struct {
+	Normal   linalg.Vec2
+	Depth    float64
+	Contacts [2]linalg.Vec2
+	Count    int
+}
+
+// MTV is the minimum translation vector, which is how far the first shape
+// has to move to get out of the second.
+func (m Manifold) MTV() linalg.Vec2 {
+	return m.Normal.Mul(-m.Depth)
+}
+
+// Overlaps reports whether the shapes overlap, which is cheaper than Collide
+// when the details aren't needed.
+func Overlaps(a, b Shape) bool {
+	if a, ok := a.(AABB); ok {
+		if b, ok := b.(AABB); ok {
+			return a.Min.X < b.Max.X && b.Min.X < a.Max.X && a.Min.Y < b.Max.Y && b.Min.Y < a.Max.Y
+		}
+	}
+
+	if a, ok := a.(Circle); ok {
+		if b, ok := b.(Circle); ok {
+			r := a.Radius + b.Radius
+
+			return a.Centre.DistanceSq(b.Centre) < r*r
+		}
+	}
+
+	_, ok := Collide(a, b)
+
+	return ok
+}
+
+// Collide reports whether the shapes overlap and if so, how.
+// Shapes that are only touching don't count as overlapping.
+func Collide(a, b Shape) (Manifold, bool) {
+	// We can skip the general test entirely for two circles
+	if a, ok := a.(Circle); ok {
+		if b, ok := b.(Circle); ok {
+			return collideCircles(a, b)
+		}
+	}
+
+	return collideHulls(a.hull(), b.hull())
+}
+
+func collideCircles(a, b Circle) (Manifold, bool) {
+	r := a.Radius + b.Radius
+	between := b.Centre.Sub(a.Centre)
+
+	distanceSq := between.MagSq()
+	if distanceSq >= r*r {
+		return Manifold{}, false
+	}
+
+	// Circles on top of each other have no direction between them, so we
+	// just have to pick one
+	normal := linalg.New(0, -1)
+	if distanceSq > 0 {
+		normal = between.Norm()
+	}
+
+	m := Manifold{
+		Normal: normal,
+		Depth:  r - math.Sqrt(distanceSq),
+		Count:  1,
+	}
+
+	m.Contacts[0] = b.Centre.Sub(normal.Mul(b.Radius))
+
+	return m, true
+}
+
+func (h hull) project(axis linalg.Vec2) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range h.points {
+		d := p.Dot(axis)
+		lo = math.Min(lo, d)
+		hi = math.Max(hi, d)
+	}
+
+	return lo - h.radius, hi + h.radius
+}
+
+func (h hull) edges(f func(a, b linalg.Vec2)) {
+	switch len(h.points) {
+	case 0, 1:
+		return
+	case 2:
+		f(h.points[0], h.points[1])
+	default:
+		for i, a := range h.points {
+			f(a, h.points[(i+1)%len(h.points)])
+		}
+	}
+}
+
+func (h hull) closest(p linalg.Vec2) linalg.Vec2 {
+	if len(h.points) == 1 {
+		return h.points[0]
+	}
+
+	best := h.points[0]
+	bestSq := math.Inf(1)
+
+	h.edges(func(a, b linalg.Vec2) {
+		c := closestOnSegment(p, a, b)
+		if d := c.DistanceSq(p); d < bestSq {
+			best, bestSq = c, d
+		}
+	})
+
+	return best
+}
+
+// collideHulls is the separating axis test, where two convex shapes don't
+// overlap if there's any axis where their projections don't overlap.
+//
+// For polygons the axes to try are the edge normals, and for rounded shapes
+// we also need the directions from each point of one shape to the closest
+// point on the other.
+func collideHulls(a, b hull) (Manifold, bool) {
+	if len(a.points) == 0 || len(b.points) == 0 {
+		return Manifold{}, false
+	}
+
+	best := Manifold{Depth: math.Inf(1)}
+	isSeparated := false
+
+	test := func(axis linalg.Vec2) {
+		axis = axis.Norm()
+		if isSeparated || axis == (linalg.Vec2{}) {
+			return
+		}
+
+		minA, maxA := a.project(axis)
+		minB, maxB := b.project(axis)
+
+		// Either b is pushed along the axis or against it, and we want
+		// whichever of them is shorter
+		forward := maxA - minB
+		backward := maxB - minA
+
+		if forward <= 0 || backward <= 0 {
+			isSeparated = true
+
+			return
+		}
+
+		if forward < best.Depth {
+			best.Depth = forward
+			best.Normal = axis
+		}
+
+		if backward < best.Depth {
+			best.Depth = backward
+			best.Normal = axis.Neg()
+		}
+	}
+
+	for _, h := range []hull{a, b} {
+		h.edges(func(p, q linalg.Vec2) {
+			test(q.Sub(p).Perp())
+		})
+	}
+
+	for _, p := range a.points {
+		test(b.closest(p).Sub(p))
+	}
+
+	for _, p := range b.points {
+		test(p.Sub(a.closest(p)))
+	}
+
+	if isSeparated {
+		return Manifold{}, false
+	}
+
+	best.Contacts, best.Count = contacts(a, b, best.Normal)
+
+	return best, true
+}
+
+// support returns the points of the hull that are furthest along the
+// direction, of which there are two when an edge faces that way.
+func (h hull) support(direction linalg.Vec2) []linalg.Vec2 {
+	const tolerance = 1e-6
+
+	furthest := math.Inf(-1)
+	for _, p := range h.points {
+		furthest = math.Max(furthest, p.Dot(direction))
+	}
+
+	var points []linalg.Vec2
+	for _, p := range h.points {
+		if p.Dot(direction) >= furthest-tolerance {
+			points = append(points, p.Add(direction.Mul(h.radius)))
+		}
+	}
+
+	return points
+}
+
+func contacts(a, b hull, normal linalg.Vec2) ([2]linalg.Vec2, int) {
+	var out [2]linalg.Vec2
+
+	incident := b.support(normal.Neg())
+	if len(incident) == 1 {
+		out[0] = incident[0]
+
+		return out, 1
+	}
+
+	reference := a.support(normal)
+	if len(reference) == 1 {
+		out[0] = reference[0]
+
+		return out, 1
+	}
+
+	// Two edges lying against each other touch along the part where they
+	// overlap, so the contacts are the ends of that part on b's edge
+	tangent := normal.Perp()
+
+	lo, hi := math.Inf(-1), math.Inf(1)
+	for _, edge := range [][]linalg.Vec2{reference, incident} {
+		edgeLo, edgeHi := math.Inf(1), math.Inf(-1)
+		for _, p := range edge {
+			edgeLo = math.Min(edgeLo, p.Dot(tangent))
+			edgeHi = math.Max(edgeHi, p.Dot(tangent))
+		}
+
+		lo = math.Max(lo, edgeLo)
+		hi = math.Min(hi, edgeHi)
+	}
+
+	depth := incident[0].Dot(normal)
+
+	out[0] = tangent.Mul(lo).Add(normal.Mul(depth))
+	out[1] = tangent.Mul(hi).Add(normal.Mul(depth))
+
+	if lo >= hi {
+		return out, 1
+	}
+
+	return out, 2
+}
@@ -0,0 +1,187 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+func isNear(a, b linalg.Vec2) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func box(minX, minY, maxX, maxY float64) AABB {
+	return AABB{Min: linalg.New(minX, minY), Max: linalg.New(maxX, maxY)}
+}
+
+func TestCollide(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     Shape
+		normal   linalg.Vec2
+		depth    float64
+		contacts []linalg.Vec2
+	}{
+		{
+			name:     "aabb edge on edge",
+			a:        box(0, 0, 10, 10),
+			b:        box(8, 2, 18, 8),
+			normal:   linalg.New(1, 0),
+			depth:    2,
+			contacts: []linalg.Vec2{linalg.New(8, 2), linalg.New(8, 8)},
+		},
+		{
+			name:     "aabb below",
+			a:        box(0, 0, 10, 10),
+			b:        box(2, 7, 8, 20),
+			normal:   linalg.New(0, 1),
+			depth:    3,
+			contacts: []linalg.Vec2{linalg.New(8, 7), linalg.New(2, 7)},
+		},
+		{
+			name:     "aabb and polygon corner",
+			a:        box(0, 0, 10, 10),
+			b:        NewPolygon(linalg.New(9, 5), linalg.New(12, 2), linalg.New(15, 5), linalg.New(12, 8)),
+			normal:   linalg.New(1, 0),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(9, 5)},
+		},
+		{
+			name:     "polygon corner and aabb",
+			a:        NewPolygon(linalg.New(9, 5), linalg.New(12, 2), linalg.New(15, 5), linalg.New(12, 8)),
+			b:        box(0, 0, 10, 10),
+			normal:   linalg.New(-1, 0),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(9, 5)},
+		},
+		{
+			name:     "circles",
+			a:        Circle{Centre: linalg.New(0, 0), Radius: 2},
+			b:        Circle{Centre: linalg.New(3, 0), Radius: 2},
+			normal:   linalg.New(1, 0),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(1, 0)},
+		},
+		{
+			name:     "circle and aabb",
+			a:        Circle{Centre: linalg.New(0, 5), Radius: 3},
+			b:        box(2, 0, 10, 10),
+			normal:   linalg.New(1, 0),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(3, 5)},
+		},
+		{
+			name:     "capsule side and aabb",
+			a:        Capsule{A: linalg.New(0, 0), B: linalg.New(0, 10), Radius: 2},
+			b:        box(1, 3, 6, 7),
+			normal:   linalg.New(1, 0),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(1, 3), linalg.New(1, 7)},
+		},
+		{
+			name:     "segment and circle",
+			a:        Segment{A: linalg.New(0, 0), B: linalg.New(10, 0)},
+			b:        Circle{Centre: linalg.New(5, 1), Radius: 2},
+			normal:   linalg.New(0, 1),
+			depth:    1,
+			contacts: []linalg.Vec2{linalg.New(5, -1)},
+		},
+		{
+			name: "polygons wound either way",
+			a:    NewPolygon(linalg.New(0, 0), linalg.New(10, 0), linalg.New(0, 10)),
+			b:    NewPolygon(linalg.New(4, -5), linalg.New(4, 20), linalg.New(20, 20), linalg.New(20, -5)),
+			// Only the corner of the triangle at (10, 0) is inside b, so
+			// there's a single contact even though b's edge faces it
+			normal:   linalg.New(1, 0),
+			depth:    6,
+			contacts: []linalg.Vec2{linalg.New(10, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, ok := Collide(test.a, test.b)
+			if !ok {
+				t.Fatal("Collide reported no overlap")
+			}
+
+			if !isNear(m.Normal, test.normal) {
+				t.Errorf("Normal = %v, want %v", m.Normal, test.normal)
+			}
+
+			if math.Abs(m.Depth-test.depth) > 1e-9 {
+				t.Errorf("Depth = %v, want %v", m.Depth, test.depth)
+			}
+
+			if m.Count != len(test.contacts) {
+				t.Fatalf("Count = %d, want %d", m.Count, len(test.contacts))
+			}
+
+			for i, want := range test.contacts {
+				if !isNear(m.Contacts[i], want) {
+					t.Errorf("Contacts[%d] = %v, want %v", i, m.Contacts[i], want)
+				}
+			}
+
+			// Moving the first shape by the MTV leaves them only touching
+			moved, ok := Collide(translate(test.a, m.MTV()), test.b)
+			if ok && moved.Depth > 1e-9 {
+				t.Errorf("shapes still overlap by %v after moving by the MTV %v", moved.Depth, m.MTV())
+			}
+
+			// Swapping the shapes flips the normal
+			swapped, ok := Collide(test.b, test.a)
+			if !ok || !isNear(swapped.Normal, test.normal.Neg()) || math.Abs(swapped.Depth-test.depth) > 1e-9 {
+				t.Errorf("swapped = %v, %v, want a normal of %v and depth of %v", swapped, ok, test.normal.Neg(), test.depth)
+			}
+		})
+	}
+}
+
+func translate(shape Shape, offset linalg.Vec2) Shape {
+	switch s := shape.(type) {
+	case AABB:
+		return s.Translate(offset)
+	case Circle:
+		return Circle{Centre: s.Centre.Add(offset), Radius: s.Radius}
+	case Segment:
+		return Segment{A: s.A.Add(offset), B: s.B.Add(offset)}
+	case Capsule:
+		return Capsule{A: s.A.Add(offset), B: s.B.Add(offset), Radius: s.Radius}
+	case Polygon:
+		points := make([]linalg.Vec2, len(s.Points))
+		for i, p := range s.Points {
+			points[i] = p.Add(offset)
+		}
+
+		return Polygon{Points: points}
+	}
+
+	panic("unknown shape")
+}
+
+func TestCollideTouchingIsNotOverlapping(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Shape
+	}{
+		{"aabb edges", box(0, 0, 10, 10), box(10, 0, 20, 10)},
+		{"aabb corners", box(0, 0, 10, 10), box(10, 10, 20, 20)},
+		{"circles", Circle{Centre: linalg.New(0, 0), Radius: 1}, Circle{Centre: linalg.New(2, 0), Radius: 1}},
+		{"circle and aabb", Circle{Centre: linalg.New(-1, 5), Radius: 1}, box(0, 0, 10, 10)},
+		{"apart", box(0, 0, 1, 1), Circle{Centre: linalg.New(5, 5), Radius: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := Collide(test.a, test.b); ok {
+				t.Error("Collide reported an overlap")
+			}
+
+			if Overlaps(test.a, test.b) {
+				t.Error("Overlaps reported an overlap")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// Grid is a grid of tiles where some of them are solid, such as a
+// gfx.TileMap.
+type Grid interface {
+	IsSolid(x, y int) bool
+	TileSize() (width, height int)
+}
+
+// Movement is the result of moving a box through a grid.
+//
+// Normal points away from whatever the box ran into on each axis, so a
+// Normal.Y of -1 means it landed on the floor.
+type Movement struct {
+	Box        AABB
+	Normal     linalg.Vec2
+	IsBlockedX bool
+	IsBlockedY bool
+}
+
+// tileRange returns the tiles that the span from lo to hi covers, where hi
+// is exclusive so that a box exactly on the edge of a tile isn't in it.
+func tileRange(lo, hi, size float64) (int, int) {
+	return int(math.Floor(lo / size)), int(math.Ceil(hi/size)) - 1
+}
+
+// MoveAABB moves the box by velocity, stopping it against any solid tiles
+// in the way.
+//
+// The box is moved along X and then along Y so that it slides along walls
+// and floors, and every tile it passes over is checked so that it can't move
+// through a wall no matter how fast it's going.
+func MoveAABB(grid Grid, box AABB, velocity linalg.Vec2) Movement {
+	tileWidth, tileHeight := grid.TileSize()
+	w, h := float64(tileWidth), float64(tileHeight)
+
+	m := Movement{Box: box}
+
+	if velocity.X != 0 {
+		rowLo, rowHi := tileRange(m.Box.Min.Y, m.Box.Max.Y, h)
+		solid := func(column int) bool {
+			for row := rowLo; row <= rowHi; row++ {
+				if grid.IsSolid(column, row) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		offset := velocity.X
+
+		if velocity.X > 0 {
+			from, _ := tileRange(m.Box.Max.X, m.Box.Max.X, w)
+			_, to := tileRange(m.Box.Max.X, m.Box.Max.X+velocity.X, w)
+
+			for column := from; column <= to; column++ {
+				if edge := float64(column) * w; edge >= m.Box.Max.X && solid(column) {
+					offset = edge - m.Box.Max.X
+					m.IsBlockedX = true
+					m.Normal.X = -1
+
+					break
+				}
+			}
+		} else {
+			_, from := tileRange(m.Box.Min.X, m.Box.Min.X, w)
+			to, _ := tileRange(m.Box.Min.X+velocity.X, m.Box.Min.X, w)
+
+			for column := from; column >= to; column-- {
+				if edge := float64(column+1) * w; edge <= m.Box.Min.X && solid(column) {
+					offset = edge - m.Box.Min.X
+					m.IsBlockedX = true
+					m.Normal.X = 1
+
+					break
+				}
+			}
+		}
+
+		m.Box = m.Box.Translate(linalg.New(offset, 0))
+	}
+
+	if velocity.Y != 0 {
+		columnLo, columnHi := tileRange(m.Box.Min.X, m.Box.Max.X, w)
+		solid := func(row int) bool {
+			for column := columnLo; column <= columnHi; column++ {
+				if grid.IsSolid(column, row) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		offset := velocity.Y
+
+		if velocity.Y > 0 {
+			from, _ := tileRange(m.Box.Max.Y, m.Box.Max.Y, h)
+			_, to := tileRange(m.Box.Max.Y, m.Box.Max.Y+velocity.Y, h)
+
+			for row := from; row <= to; row++ {
+				if edge := float64(row) * h; edge >= m.Box.Max.Y && solid(row) {
+					offset = edge - m.Box.Max.Y
+					m.IsBlockedY = true
+					m.Normal.Y = -1
+
+					break
+				}
+			}
+		} else {
+			_, from := tileRange(m.Box.Min.Y, m.Box.Min.Y, h)
+			to, _ := tileRange(m.Box.Min.Y+velocity.Y, m.Box.Min.Y, h)
+
+			for row := from; row >= to; row-- {
+				if edge := float64(row+1) * h; edge <= m.Box.Min.Y && solid(row) {
+					offset = edge - m.Box.Min.Y
+					m.IsBlockedY = true
+					m.Normal.Y = 1
+
+					break
+				}
+			}
+		}
+
+		m.Box = m.Box.Translate(linalg.New(0, offset))
+	}
+
+	return m
+}
+
+// CollideGrid returns how the shape overlaps each of the solid tiles it's
+// touching, for shapes that can't be moved with MoveAABB.
+// The manifolds are from the shape to the tiles.
+func CollideGrid(grid Grid, shape Shape) []Manifold {
+	tileWidth, tileHeight := grid.TileSize()
+	w, h := float64(tileWidth), float64(tileHeight)
+
+	bounds := shape.Bounds()
+	columnLo, columnHi := tileRange(bounds.Min.X, bounds.Max.X, w)
+	rowLo, rowHi := tileRange(bounds.Min.Y, bounds.Max.Y, h)
+
+	var manifolds []Manifold
+
+	for row := rowLo; row <= rowHi; row++ {
+		for column := columnLo; column <= columnHi; column++ {
+			if !grid.IsSolid(column, row) {
+				continue
+			}
+
+			tile := AABB{
+				Min: linalg.New(float64(column)*w, float64(row)*h),
+				Max: linalg.New(float64(column+1)*w, float64(row+1)*h),
+			}
+
+			if m, ok := Collide(shape, tile); ok {
+				manifolds = append(manifolds, m)
+			}
+		}
+	}
+
+	return manifolds
+}
@@ -0,0 +1,91 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+type testGrid map[[2]int]bool
+
+func (g testGrid) IsSolid(x, y int) bool {
+	return g[[2]int{x, y}]
+}
+
+func (g testGrid) TileSize() (int, int) {
+	return 16, 16
+}
+
+func TestMoveAABB(t *testing.T) {
+	// A column of wall at x 80 to 96 and a row of floor at y 80 to 96, both
+	// well away from where the boxes start
+	grid := testGrid{}
+	for i := -20; i <= 20; i++ {
+		grid[[2]int{5, i}] = true
+		grid[[2]int{i, 5}] = true
+	}
+
+	tests := []struct {
+		name      string
+		box       AABB
+		velocity  linalg.Vec2
+		want      AABB
+		normal    linalg.Vec2
+		isBlocked [2]bool
+	}{
+		{"free", box(0, 0, 8, 8), linalg.New(10, 20), box(10, 20, 18, 28), linalg.Vec2{}, [2]bool{}},
+		{"right tunnelling", box(0, 0, 8, 8), linalg.New(1000, 0), box(72, 0, 80, 8), linalg.New(-1, 0), [2]bool{true, false}},
+		{"left tunnelling", box(200, 0, 208, 8), linalg.New(-1000, 0), box(96, 0, 104, 8), linalg.New(1, 0), [2]bool{true, false}},
+		{"down tunnelling", box(0, 0, 8, 8), linalg.New(0, 1000), box(0, 72, 8, 80), linalg.New(0, -1), [2]bool{false, true}},
+		{"up tunnelling", box(0, 200, 8, 208), linalg.New(0, -1000), box(0, 96, 8, 104), linalg.New(0, 1), [2]bool{false, true}},
+		{"right onto edge", box(0, 0, 8, 8), linalg.New(72, 0), box(72, 0, 80, 8), linalg.Vec2{}, [2]bool{}},
+		{"left onto edge", box(200, 0, 208, 8), linalg.New(-104, 0), box(96, 0, 104, 8), linalg.Vec2{}, [2]bool{}},
+		{"down onto edge", box(0, 0, 8, 8), linalg.New(0, 72), box(0, 72, 8, 80), linalg.Vec2{}, [2]bool{}},
+		{"up onto edge", box(0, 200, 8, 208), linalg.New(0, -104), box(0, 96, 8, 104), linalg.Vec2{}, [2]bool{}},
+		{"right from edge", box(72, 0, 80, 8), linalg.New(1, 0), box(72, 0, 80, 8), linalg.New(-1, 0), [2]bool{true, false}},
+		{"left from edge", box(96, 0, 104, 8), linalg.New(-1, 0), box(96, 0, 104, 8), linalg.New(1, 0), [2]bool{true, false}},
+		{"down from edge", box(0, 72, 8, 80), linalg.New(0, 1), box(0, 72, 8, 80), linalg.New(0, -1), [2]bool{false, true}},
+		{"up from edge", box(0, 96, 8, 104), linalg.New(0, -1), box(0, 96, 8, 104), linalg.New(0, 1), [2]bool{false, true}},
+		{"away from edge", box(72, 72, 80, 80), linalg.New(-10, -10), box(62, 62, 70, 70), linalg.Vec2{}, [2]bool{}},
+		{"sliding along floor", box(0, 72, 8, 80), linalg.New(40, 5), box(40, 72, 48, 80), linalg.New(0, -1), [2]bool{false, true}},
+		{"sliding down wall", box(72, 0, 80, 8), linalg.New(5, 40), box(72, 40, 80, 48), linalg.New(-1, 0), [2]bool{true, false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := MoveAABB(grid, test.box, test.velocity)
+
+			if !isNear(m.Box.Min, test.want.Min) || !isNear(m.Box.Max, test.want.Max) {
+				t.Errorf("Box = %v, want %v", m.Box, test.want)
+			}
+
+			if m.Normal != test.normal {
+				t.Errorf("Normal = %v, want %v", m.Normal, test.normal)
+			}
+
+			if m.IsBlockedX != test.isBlocked[0] || m.IsBlockedY != test.isBlocked[1] {
+				t.Errorf("IsBlocked = %v, %v, want %v", m.IsBlockedX, m.IsBlockedY, test.isBlocked)
+			}
+		})
+	}
+}
+
+func TestCollideGrid(t *testing.T) {
+	grid := testGrid{{1, 0}: true, {1, 1}: true}
+
+	manifolds := CollideGrid(grid, Circle{Centre: linalg.New(14, 16), Radius: 4})
+	if len(manifolds) != 2 {
+		t.Fatalf("got %d manifolds, want 2", len(manifolds))
+	}
+
+	for _, m := range manifolds {
+		if m.Normal.X <= 0 {
+			t.Errorf("Normal = %v, want it to point from the circle into the tiles", m.Normal)
+		}
+	}
+
+	// Exactly touching a tile doesn't count
+	if manifolds := CollideGrid(grid, box(8, 0, 16, 8)); len(manifolds) != 0 {
+		t.Errorf("got %d manifolds for a box touching a tile, want 0", len(manifolds))
+	}
+}
@@ -0,0 +1,270 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// Ray goes from Origin to Origin + Direction, so the length of Direction is
+// how far it reaches.
+type Ray struct {
+	Origin    linalg.Vec2
+	Direction linalg.Vec2
+}
+
+func (r Ray) At(t float64) linalg.Vec2 {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// Hit is where a ray or a moving shape hits something.
+//
+// Time is how far along the ray the hit is, from 0 at its origin to 1 at its
+// end, and Normal is the unit normal of the surface that was hit.
+// Anything that starts inside a shape hits it at a time of 0 with no normal.
+//
+// The same as with Collide, the boundary of a shape isn't part of it, so a
+// ray that only touches a shape, by grazing a corner or running along a side,
+// doesn't hit it.
+// A ray that starts on the boundary hits at a time of 0 with a normal if it
+// goes in, and doesn't hit at all if it goes out.
+type Hit struct {
+	Point  linalg.Vec2
+	Normal linalg.Vec2
+	Time   float64
+}
+
+func (a AABB) Raycast(ray Ray) (Hit, bool) {
+	// The slab method, where the ray is inside the box for the part of it
+	// that's between both pairs of sides at the same time
+	enter, exit := math.Inf(-1), math.Inf(1)
+
+	var normal linalg.Vec2
+
+	axes := [2]struct {
+		origin, direction, min, max float64
+		normal                      linalg.Vec2
+	}{
+		{ray.Origin.X, ray.Direction.X, a.Min.X, a.Max.X, linalg.New(1, 0)},
+		{ray.Origin.Y, ray.Direction.Y, a.Min.Y, a.Max.Y, linalg.New(0, 1)},
+	}
+
+	for _, axis := range axes {
+		if axis.direction == 0 {
+			// Running along a side doesn't count as going in, which lets
+			// boxes slide along each other
+			if axis.origin <= axis.min || axis.origin >= axis.max {
+				return Hit{}, false
+			}
+
+			continue
+		}
+
+		near := (axis.min - axis.origin) / axis.direction
+		far := (axis.max - axis.origin) / axis.direction
+		side := axis.normal.Neg()
+
+		if near > far {
+			near, far = far, near
+			side = axis.normal
+		}
+
+		if near > enter {
+			enter = near
+			normal = side
+		}
+
+		exit = math.Min(exit, far)
+	}
+
+	return finishHit(ray, enter, exit, normal)
+}
+
+// finishHit turns the times a ray enters and leaves a shape into a hit.
+func finishHit(ray Ray, enter, exit float64, normal linalg.Vec2) (Hit, bool) {
+	// Leaving right where the ray starts means it's only touching the shape
+	// on the way out, and leaving where it goes in means it's only touching
+	// a corner, neither of which are a hit
+	if enter >= exit || exit <= 0 || enter > 1 {
+		return Hit{}, false
+	}
+
+	if enter < 0 {
+		return Hit{Point: ray.Origin}, true
+	}
+
+	return Hit{Point: ray.At(enter), Normal: normal, Time: enter}, true
+}
+
+func (c Circle) Raycast(ray Ray) (Hit, bool) {
+	offset := ray.Origin.Sub(c.Centre)
+
+	if offset.MagSq() < c.Radius*c.Radius {
+		return Hit{Point: ray.Origin}, true
+	}
+
+	// Solving |offset + direction*t| = radius for t gives a quadratic, where
+	// the smaller root is where the ray goes in, and a single root means it
+	// only touches the side
+	a := ray.Direction.MagSq()
+	b := 2 * offset.Dot(ray.Direction)
+	cc := offset.MagSq() - c.Radius*c.Radius
+
+	discriminant := b*b - 4*a*cc
+	if a == 0 || discriminant <= 0 {
+		return Hit{}, false
+	}
+
+	t := (-b - math.Sqrt(discriminant)) / (2 * a)
+	if t < 0 || t > 1 {
+		return Hit{}, false
+	}
+
+	point := ray.At(t)
+
+	return Hit{Point: point, Normal: point.Sub(c.Centre).Norm(), Time: t}, true
+}
+
+func (s Segment) Raycast(ray Ray) (Hit, bool) {
+	edge := s.B.Sub(s.A)
+
+	denominator := ray.Direction.Cross(edge)
+	if denominator == 0 {
+		return Hit{}, false
+	}
+
+	// Where the lines cross as a fraction along each of them
+	between := s.A.Sub(ray.Origin)
+	t := between.Cross(edge) / denominator
+	u := between.Cross(ray.Direction) / denominator
+
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Hit{}, false
+	}
+
+	// The normal faces whichever side the ray came from
+	normal := edge.Perp().Norm()
+	if normal.Dot(ray.Direction) > 0 {
+		normal = normal.Neg()
+	}
+
+	return Hit{Point: ray.At(t), Normal: normal, Time: t}, true
+}
+
+func (c Capsule) Raycast(ray Ray) (Hit, bool) {
+	closest := closestOnSegment(ray.Origin, c.A, c.B)
+	distanceSq := ray.Origin.DistanceSq(closest)
+
+	if distanceSq < c.Radius*c.Radius {
+		return Hit{Point: ray.Origin}, true
+	}
+
+	// The sides are segments, which can't tell going in from going out, so
+	// starting on the boundary has to be checked here
+	if distanceSq == c.Radius*c.Radius && ray.Direction.Dot(ray.Origin.Sub(closest)) >= 0 {
+		return Hit{}, false
+	}
+
+	// A capsule is its two end circles joined by the sides of a rectangle,
+	// and whichever of those the ray hits first is where it goes in
+	side := c.B.Sub(c.A).Perp().Norm().Mul(c.Radius)
+
+	shapes := []Shape{
+		Circle{Centre: c.A, Radius: c.Radius},
+		Circle{Centre: c.B, Radius: c.Radius},
+		Segment{A: c.A.Add(side), B: c.B.Add(side)},
+		Segment{A: c.A.Sub(side), B: c.B.Sub(side)},
+	}
+
+	var best Hit
+	isHit := false
+
+	for _, shape := range shapes {
+		hit, ok := shape.Raycast(ray)
+		if ok && (!isHit || hit.Time < best.Time) {
+			best, isHit = hit, true
+		}
+	}
+
+	return best, isHit
+}
+
+func (p Polygon) Raycast(ray Ray) (Hit, bool) {
+	if len(p.Points) < 3 {
+		return Hit{}, false
+	}
+
+	// The Cyrus-Beck algorithm, where each edge either cuts off the start of
+	// the ray as it goes in or the end of it as it comes out
+	enter, exit := math.Inf(-1), math.Inf(1)
+	w := winding(p.Points)
+
+	var normal linalg.Vec2
+
+	for i, a := range p.Points {
+		n := outwardNormal(a, p.Points[(i+1)%len(p.Points)], w)
+
+		denominator := n.Dot(ray.Direction)
+		distance := n.Dot(a.Sub(ray.Origin))
+
+		if denominator == 0 {
+			// Running along an edge is only touching it, the same as for
+			// an AABB
+			if distance <= 0 {
+				return Hit{}, false
+			}
+
+			continue
+		}
+
+		t := distance / denominator
+
+		if denominator < 0 {
+			if t > enter {
+				enter = t
+				normal = n
+			}
+		} else {
+			exit = math.Min(exit, t)
+		}
+	}
+
+	return finishHit(ray, enter, exit, normal)
+}
+
+// Raycast returns the first shape the ray hits along with where it hits it.
+func Raycast(ray Ray, shapes []Shape) (int, Hit, bool) {
+	best, bestIndex := Hit{}, -1
+
+	for i, shape := range shapes {
+		hit, ok := shape.Raycast(ray)
+		if ok && (bestIndex < 0 || hit.Time < best.Time) {
+			best, bestIndex = hit, i
+		}
+	}
+
+	return bestIndex, best, bestIndex >= 0
+}
+
+// Sweep moves box by velocity and returns where it first hits other, which
+// stops fast moving boxes from passing straight through thin walls.
+// The point is on the edge of box that does the hitting, except when the
+// boxes already overlap, where the hit is at a time of 0 with no normal and
+// the point is the centre of box.
+func Sweep(box AABB, velocity linalg.Vec2, other AABB) (Hit, bool) {
+	// Growing the other box by half the size of the moving one means we only
+	// have to follow the centre of the moving box
+	half := box.Size().Mul(0.5)
+	expanded := AABB{Min: other.Min.Sub(half), Max: other.Max.Add(half)}
+
+	hit, ok := expanded.Raycast(Ray{Origin: box.Centre(), Direction: velocity})
+	if !ok {
+		return Hit{}, false
+	}
+
+	// The point is where the centre of the box ends up, so we move it to the
+	// edge of the box that does the hitting
+	hit.Point = hit.Point.Sub(linalg.New(hit.Normal.X*half.X, hit.Normal.Y*half.Y))
+
+	return hit, true
+}
@@ -0,0 +1,173 @@
+package collision
+
+import (
+	"math"
+	"testing"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+func ray(x, y, dx, dy float64) Ray {
+	return Ray{Origin: linalg.New(x, y), Direction: linalg.New(dx, dy)}
+}
+
+func TestRaycast(t *testing.T) {
+	square := box(0, 0, 10, 10)
+	circle := Circle{Centre: linalg.New(0, 0), Radius: 5}
+	capsule := Capsule{A: linalg.New(0, 0), B: linalg.New(10, 0), Radius: 2}
+	triangle := NewPolygon(linalg.New(0, 0), linalg.New(10, 0), linalg.New(0, 10))
+	reversed := NewPolygon(linalg.New(0, 10), linalg.New(10, 0), linalg.New(0, 0))
+	segment := Segment{A: linalg.New(0, 0), B: linalg.New(0, 10)}
+
+	diagonal := linalg.New(1, 1).Norm()
+
+	tests := []struct {
+		name   string
+		shape  Shape
+		ray    Ray
+		isHit  bool
+		time   float64
+		normal linalg.Vec2
+	}{
+		{"aabb from left", square, ray(-5, 5, 10, 0), true, 0.5, linalg.New(-1, 0)},
+		{"aabb from right", square, ray(15, 5, -10, 0), true, 0.5, linalg.New(1, 0)},
+		{"aabb from above", square, ray(5, -5, 0, 10), true, 0.5, linalg.New(0, -1)},
+		{"aabb from below", square, ray(5, 15, 0, -10), true, 0.5, linalg.New(0, 1)},
+		{"aabb inside", square, ray(5, 5, 10, 0), true, 0, linalg.Vec2{}},
+		{"aabb on side going in", square, ray(0, 5, 10, 0), true, 0, linalg.New(-1, 0)},
+		{"aabb on side going out", square, ray(0, 5, -10, 0), false, 0, linalg.Vec2{}},
+		{"aabb along side", square, ray(-5, 0, 20, 0), false, 0, linalg.Vec2{}},
+		{"aabb through corner", square, ray(-5, 5, 10, -10), false, 0, linalg.Vec2{}},
+		{"aabb too short", square, ray(-5, 5, 4, 0), false, 0, linalg.Vec2{}},
+		{"aabb pointing away", square, ray(-5, 5, -10, 0), false, 0, linalg.Vec2{}},
+
+		{"circle from left", circle, ray(-10, 0, 10, 0), true, 0.5, linalg.New(-1, 0)},
+		{"circle inside", circle, ray(1, 0, 10, 0), true, 0, linalg.Vec2{}},
+		{"circle on edge going in", circle, ray(-5, 0, 1, 0), true, 0, linalg.New(-1, 0)},
+		{"circle on edge going out", circle, ray(-5, 0, -1, 0), false, 0, linalg.Vec2{}},
+		{"circle on edge going along", circle, ray(-5, 0, 0, 1), false, 0, linalg.Vec2{}},
+		{"circle tangent", circle, ray(-10, 5, 20, 0), false, 0, linalg.Vec2{}},
+		{"circle too short", circle, ray(-10, 0, 4, 0), false, 0, linalg.Vec2{}},
+
+		{"capsule side", capsule, ray(5, -10, 0, 10), true, 0.8, linalg.New(0, -1)},
+		{"capsule end", capsule, ray(-10, 0, 10, 0), true, 0.8, linalg.New(-1, 0)},
+		{"capsule inside", capsule, ray(5, 0, 0, 10), true, 0, linalg.Vec2{}},
+		{"capsule on side going in", capsule, ray(5, -2, 0, 1), true, 0, linalg.New(0, -1)},
+		{"capsule on side going out", capsule, ray(5, -2, 0, -1), false, 0, linalg.Vec2{}},
+		{"capsule on side going along", capsule, ray(5, -2, 1, 0), false, 0, linalg.Vec2{}},
+		{"capsule on end going out", capsule, ray(12, 0, 1, 0), false, 0, linalg.Vec2{}},
+		{"capsule along side", capsule, ray(-5, -2, 20, 0), false, 0, linalg.Vec2{}},
+
+		{"polygon from left", triangle, ray(-5, 2, 10, 0), true, 0.5, linalg.New(-1, 0)},
+		{"polygon hypotenuse", triangle, ray(10, 10, -10, -10), true, 0.5, diagonal},
+		{"polygon reversed hypotenuse", reversed, ray(10, 10, -10, -10), true, 0.5, diagonal},
+		{"polygon inside", triangle, ray(1, 1, 10, 0), true, 0, linalg.Vec2{}},
+		{"polygon on edge going in", triangle, ray(5, 0, 0, 1), true, 0, linalg.New(0, -1)},
+		{"polygon on edge going out", triangle, ray(5, 0, 0, -1), false, 0, linalg.Vec2{}},
+		{"polygon along edge", triangle, ray(-5, 0, 20, 0), false, 0, linalg.Vec2{}},
+		{"polygon along hypotenuse", triangle, ray(15, -5, -10, 10), false, 0, linalg.Vec2{}},
+		{"polygon through corner", triangle, ray(10, -5, 0, 10), false, 0, linalg.Vec2{}},
+
+		{"segment from left", segment, ray(-5, 5, 10, 0), true, 0.5, linalg.New(-1, 0)},
+		{"segment from right", segment, ray(5, 5, -10, 0), true, 0.5, linalg.New(1, 0)},
+		{"segment end", segment, ray(-5, 10, 10, 0), true, 0.5, linalg.New(-1, 0)},
+		{"segment along", segment, ray(0, -5, 0, 20), false, 0, linalg.Vec2{}},
+		{"segment past end", segment, ray(-5, 11, 10, 0), false, 0, linalg.Vec2{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, ok := test.shape.Raycast(test.ray)
+			if ok != test.isHit {
+				t.Fatalf("Raycast(%v) = %v, %v, want a hit of %v", test.ray, hit, ok, test.isHit)
+			}
+
+			if !ok {
+				return
+			}
+
+			if math.Abs(hit.Time-test.time) > 1e-9 {
+				t.Errorf("Time = %v, want %v", hit.Time, test.time)
+			}
+
+			if !isNear(hit.Normal, test.normal) {
+				t.Errorf("Normal = %v, want %v", hit.Normal, test.normal)
+			}
+
+			if !isNear(hit.Point, test.ray.At(test.time)) {
+				t.Errorf("Point = %v, want %v", hit.Point, test.ray.At(test.time))
+			}
+		})
+	}
+}
+
+func TestRaycastClosest(t *testing.T) {
+	shapes := []Shape{
+		box(20, -5, 30, 5),
+		Circle{Centre: linalg.New(10, 0), Radius: 2},
+		box(-20, -5, -10, 5),
+	}
+
+	i, hit, ok := Raycast(ray(0, 0, 40, 0), shapes)
+	if !ok || i != 1 {
+		t.Fatalf("Raycast = %d, %v, %v, want the circle", i, hit, ok)
+	}
+
+	if !isNear(hit.Point, linalg.New(8, 0)) {
+		t.Errorf("Point = %v, want (8, 0)", hit.Point)
+	}
+
+	if i, _, ok := Raycast(ray(0, 0, 0, 40), shapes); ok {
+		t.Errorf("Raycast hit shape %d", i)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	moving := box(0, 0, 2, 2)
+
+	tests := []struct {
+		name     string
+		velocity linalg.Vec2
+		other    AABB
+		isHit    bool
+		time     float64
+		normal   linalg.Vec2
+		point    linalg.Vec2
+	}{
+		{"right", linalg.New(10, 0), box(5, 0, 7, 2), true, 0.3, linalg.New(-1, 0), linalg.New(5, 1)},
+		{"left", linalg.New(-10, 0), box(-7, 0, -5, 2), true, 0.5, linalg.New(1, 0), linalg.New(-5, 1)},
+		{"down", linalg.New(0, 10), box(0, 6, 2, 8), true, 0.4, linalg.New(0, -1), linalg.New(1, 6)},
+		{"up", linalg.New(0, -10), box(0, -8, 2, -6), true, 0.6, linalg.New(0, 1), linalg.New(1, -6)},
+		{"through a thin wall", linalg.New(1000, 0), box(500, -10, 501, 10), true, 0.498, linalg.New(-1, 0), linalg.New(500, 1)},
+		{"too short", linalg.New(1, 0), box(5, 0, 7, 2), false, 0, linalg.Vec2{}, linalg.Vec2{}},
+		{"sliding past", linalg.New(10, 0), box(3, 2, 5, 4), false, 0, linalg.Vec2{}, linalg.Vec2{}},
+		{"touching going in", linalg.New(1, 0), box(2, 0, 4, 2), true, 0, linalg.New(-1, 0), linalg.New(2, 1)},
+		{"touching going out", linalg.New(-1, 0), box(2, 0, 4, 2), false, 0, linalg.Vec2{}, linalg.Vec2{}},
+		{"overlapping", linalg.New(1, 0), box(1, 1, 3, 3), true, 0, linalg.Vec2{}, linalg.New(1, 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hit, ok := Sweep(moving, test.velocity, test.other)
+			if ok != test.isHit {
+				t.Fatalf("Sweep = %v, %v, want a hit of %v", hit, ok, test.isHit)
+			}
+
+			if !ok {
+				return
+			}
+
+			if math.Abs(hit.Time-test.time) > 1e-9 {
+				t.Errorf("Time = %v, want %v", hit.Time, test.time)
+			}
+
+			if !isNear(hit.Normal, test.normal) {
+				t.Errorf("Normal = %v, want %v", hit.Normal, test.normal)
+			}
+
+			if !isNear(hit.Point, test.point) {
+				t.Errorf("Point = %v, want %v", hit.Point, test.point)
+			}
+		})
+	}
+}
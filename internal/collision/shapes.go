@@ -0,0 +1,183 @@
+// Package collision finds where shapes overlap and where rays and moving
+// boxes hit them, with enough information to push them apart again.
+//
+// Shapes are in the same coordinates as everything else, so positive Y
+// points down, and any shape with an area is solid, so a ray or shape that
+// starts inside one counts as hitting it straight away.
+package collision
+
+import (
+	"math"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// Shape is any of the shapes in this package.
+type Shape interface {
+	Bounds() AABB
+	Raycast(ray Ray) (Hit, bool)
+	hull() hull
+}
+
+// hull is what every shape looks like to the separating axis test, which is
+// the convex hull of its points grown by the radius.
+type hull struct {
+	points []linalg.Vec2
+	radius float64
+}
+
+type AABB struct {
+	Min linalg.Vec2
+	Max linalg.Vec2
+}
+
+func NewAABB(rect linalg.Rect[float64]) AABB {
+	return AABB{
+		Min: linalg.New(rect.X, rect.Y),
+		Max: linalg.New(rect.Right(), rect.Bottom()),
+	}
+}
+
+func (a AABB) Rect() linalg.Rect[float64] {
+	return linalg.Rect[float64]{X: a.Min.X, Y: a.Min.Y, Width: a.Max.X - a.Min.X, Height: a.Max.Y - a.Min.Y}
+}
+
+func (a AABB) Centre() linalg.Vec2 {
+	return a.Min.Add(a.Max).Mul(0.5)
+}
+
+func (a AABB) Size() linalg.Vec2 {
+	return a.Max.Sub(a.Min)
+}
+
+func (a AABB) Translate(offset linalg.Vec2) AABB {
+	return AABB{Min: a.Min.Add(offset), Max: a.Max.Add(offset)}
+}
+
+func (a AABB) Bounds() AABB {
+	return a
+}
+
+func (a AABB) hull() hull {
+	return hull{points: []linalg.Vec2{
+		a.Min,
+		linalg.New(a.Max.X, a.Min.Y),
+		a.Max,
+		linalg.New(a.Min.X, a.Max.Y),
+	}}
+}
+
+type Circle struct {
+	Centre linalg.Vec2
+	Radius float64
+}
+
+func (c Circle) Bounds() AABB {
+	r := linalg.New(c.Radius, c.Radius)
+
+	return AABB{Min: c.Centre.Sub(r), Max: c.Centre.Add(r)}
+}
+
+func (c Circle) hull() hull {
+	return hull{points: []linalg.Vec2{c.Centre}, radius: c.Radius}
+}
+
+// Segment is a line between two points, which has no area.
+type Segment struct {
+	A linalg.Vec2
+	B linalg.Vec2
+}
+
+func (s Segment) Bounds() AABB {
+	return AABB{
+		Min: linalg.New(math.Min(s.A.X, s.B.X), math.Min(s.A.Y, s.B.Y)),
+		Max: linalg.New(math.Max(s.A.X, s.B.X), math.Max(s.A.Y, s.B.Y)),
+	}
+}
+
+func (s Segment) hull() hull {
+	return hull{points: []linalg.Vec2{s.A, s.B}}
+}
+
+// Capsule is every point within the radius of the segment between A and B,
+// which makes a good shape for characters since it slides over steps.
+type Capsule struct {
+	A      linalg.Vec2
+	B      linalg.Vec2
+	Radius float64
+}
+
+func (c Capsule) Bounds() AABB {
+	bounds := Segment{A: c.A, B: c.B}.Bounds()
+	r := linalg.New(c.Radius, c.Radius)
+
+	return AABB{Min: bounds.Min.Sub(r), Max: bounds.Max.Add(r)}
+}
+
+func (c Capsule) hull() hull {
+	return hull{points: []linalg.Vec2{c.A, c.B}, radius: c.Radius}
+}
+
+// Polygon is a convex polygon, where the points can go around in either
+// direction.
+type Polygon struct {
+	Points []linalg.Vec2
+}
+
+func NewPolygon(points ...linalg.Vec2) Polygon {
+	return Polygon{Points: append([]linalg.Vec2(nil), points...)}
+}
+
+func (p Polygon) Bounds() AABB {
+	if len(p.Points) == 0 {
+		return AABB{}
+	}
+
+	bounds := AABB{Min: p.Points[0], Max: p.Points[0]}
+	for _, point := range p.Points[1:] {
+		bounds.Min = linalg.New(math.Min(bounds.Min.X, point.X), math.Min(bounds.Min.Y, point.Y))
+		bounds.Max = linalg.New(math.Max(bounds.Max.X, point.X), math.Max(bounds.Max.Y, point.Y))
+	}
+
+	return bounds
+}
+
+func (p Polygon) hull() hull {
+	return hull{points: p.Points}
+}
+
+// outwardNormal is the normal of the edge from a to b that points away from
+// the polygon, where winding is the sign of the polygon's area.
+func outwardNormal(a, b linalg.Vec2, winding float64) linalg.Vec2 {
+	edge := b.Sub(a)
+
+	return linalg.New(edge.Y, -edge.X).Mul(winding).Norm()
+}
+
+// winding is 1 or -1 depending on which way the points go around.
+func winding(points []linalg.Vec2) float64 {
+	var area float64
+	for i, a := range points {
+		b := points[(i+1)%len(points)]
+		area += a.Cross(b)
+	}
+
+	if area < 0 {
+		return -1
+	}
+
+	return 1
+}
+
+func closestOnSegment(p, a, b linalg.Vec2) linalg.Vec2 {
+	ab := b.Sub(a)
+
+	lengthSq := ab.MagSq()
+	if lengthSq == 0 {
+		return a
+	}
+
+	t := math.Max(0, math.Min(p.Sub(a).Dot(ab)/lengthSq, 1))
+
+	return a.Add(ab.Mul(t))
+}
@@ -0,0 +1,132 @@
+package debug
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/robotscone/adventure/internal/event"
+	"github.com/robotscone/adventure/internal/imgui"
+)
+
+// EventLogLine is passed as the draw data of every line drawn by an EventLog
+// so that the draw function can render its text.
+// Filter lines are buttons that toggle whether a type of event is shown.
+type EventLogLine struct {
+	Text      string
+	IsFilter  bool
+	IsEnabled bool
+}
+
+// EventLog is an overlay that shows the records of an event.Tracer as a
+// scrolling log, which follows the newest records unless it's been scrolled
+// back through.
+type EventLog struct {
+	tracer      *event.Tracer
+	lineHeight  int
+	scroll      float64
+	isFollowing bool
+	hidden      map[string]bool
+	seen        map[string]bool
+	types       []string
+	lines       []int
+}
+
+func NewEventLog(tracer *event.Tracer, lineHeight int) *EventLog {
+	return &EventLog{
+		tracer:      tracer,
+		lineHeight:  lineHeight,
+		isFollowing: true,
+		hidden:      make(map[string]bool),
+		seen:        make(map[string]bool),
+	}
+}
+
+func (l *EventLog) SetTypeVisible(typ string, visible bool) {
+	l.hidden[typ] = !visible
+}
+
+func (l *EventLog) update() {
+	l.lines = l.lines[:0]
+
+	for i := 0; i < l.tracer.Len(); i++ {
+		r := l.tracer.At(i)
+
+		if r.Type != "" && !l.seen[r.Type] {
+			l.seen[r.Type] = true
+			l.types = append(l.types, r.Type)
+
+			sort.Strings(l.types)
+		}
+
+		if l.hidden[r.Type] {
+			continue
+		}
+
+		l.lines = append(l.lines, i)
+	}
+}
+
+func (l *EventLog) Draw(ui *imgui.IMGUI, x, y, width, height int) {
+	l.update()
+
+	filterWidth := width / 4
+	scrollbarWidth := l.lineHeight / 2
+	logX := x + filterWidth
+	logWidth := width - filterWidth - scrollbarWidth
+
+	ui.BeginContainer(x, y, filterWidth, height, 0, 0)
+
+	for i, typ := range l.types {
+		ui.DrawData(EventLogLine{
+			Text:      typ,
+			IsFilter:  true,
+			IsEnabled: !l.hidden[typ],
+		})
+
+		if ui.Button(0, i*l.lineHeight, filterWidth, l.lineHeight) {
+			l.hidden[typ] = !l.hidden[typ]
+		}
+	}
+
+	ui.EndContainer()
+
+	visibleLines := height / l.lineHeight
+	maxScroll := len(l.lines) - visibleLines
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+
+	if l.isFollowing || l.scroll > float64(maxScroll) {
+		l.scroll = float64(maxScroll)
+	}
+
+	// The scrollbar can't represent an empty range, so it's only shown once
+	// there are more lines than will fit
+	if maxScroll > 0 {
+		thumbHeight := height * visibleLines / len(l.lines)
+		if thumbHeight < l.lineHeight {
+			thumbHeight = l.lineHeight
+		}
+
+		if ui.VScrollbar(logX+logWidth, y, scrollbarWidth, height, thumbHeight, 0, float64(maxScroll), &l.scroll) {
+			l.isFollowing = int(l.scroll) >= maxScroll
+		}
+	}
+
+	ui.BeginContainer(logX, y, logWidth, height, 0, 0)
+
+	first := int(l.scroll)
+	for i := 0; i < visibleLines && first+i < len(l.lines); i++ {
+		r := l.tracer.At(l.lines[first+i])
+
+		text := fmt.Sprintf("%6d %-8s", r.Frame, r.Kind)
+		if r.Type != "" {
+			text += fmt.Sprintf(" %s (%d)", r.Type, r.Listeners)
+		}
+
+		ui.DrawData(EventLogLine{Text: text})
+		ui.Panel(0, i*l.lineHeight, logWidth, l.lineHeight)
+	}
+
+	ui.EndContainer()
+}
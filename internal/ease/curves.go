@@ -0,0 +1,272 @@
+package ease
+
+import (
+	"math"
+	"sort"
+)
+
+// CubicBezier creates an easing function from a cubic Bézier curve in the
+// same way as the CSS cubic-bezier function, where the curve goes from (0, 0)
+// to (1, 1) with (x1, y1) and (x2, y2) as its control points.
+// The x values are clamped to [0, 1] so that the curve is a function of t.
+func CubicBezier(x1, y1, x2, y2 float64) Func {
+	x1 = math.Max(0, math.Min(x1, 1))
+	x2 = math.Max(0, math.Min(x2, 1))
+
+	// The polynomial coefficients of each axis, so that a point on the curve
+	// is ((ax*s + bx)*s + cx)*s
+	cx := 3 * x1
+	bx := 3*(x2-x1) - cx
+	ax := 1 - cx - bx
+
+	cy := 3 * y1
+	by := 3*(y2-y1) - cy
+	ay := 1 - cy - by
+
+	sampleX := func(s float64) float64 {
+		return ((ax*s+bx)*s + cx) * s
+	}
+
+	sampleY := func(s float64) float64 {
+		return ((ay*s+by)*s + cy) * s
+	}
+
+	sampleDerivativeX := func(s float64) float64 {
+		return (3*ax*s+2*bx)*s + cx
+	}
+
+	const epsilon = 1e-7
+
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+
+		// We need the point on the curve that's at t along the x axis, which
+		// Newton's method usually finds in a few iterations
+		s := t
+		for i := 0; i < 8; i++ {
+			x := sampleX(s) - t
+			if math.Abs(x) < epsilon {
+				return sampleY(s)
+			}
+
+			d := sampleDerivativeX(s)
+			if math.Abs(d) < epsilon {
+				break
+			}
+
+			s -= x / d
+		}
+
+		// When Newton's method doesn't converge, such as when the curve is
+		// nearly flat, we fall back to a bisection which always does
+		lo, hi := 0.0, 1.0
+		s = t
+		for hi-lo > epsilon {
+			if sampleX(s) < t {
+				lo = s
+			} else {
+				hi = s
+			}
+
+			s = (lo + hi) / 2
+		}
+
+		return sampleY(s)
+	}
+}
+
+// StepJump is where the jumps of Steps happen, in the same way as the CSS
+// steps function.
+type StepJump byte
+
+const (
+	// JumpEnd holds the value of each step until the end of the step, so the
+	// last jump happens at the end
+	JumpEnd StepJump = iota
+	// JumpStart jumps at the start of each step, so the first jump happens
+	// straight away
+	JumpStart
+	// JumpNone doesn't jump at either end, so the first and last steps hold
+	// 0 and 1
+	JumpNone
+	// JumpBoth jumps at both the start and the end
+	JumpBoth
+)
+
+// Steps creates an easing function that moves in n equal steps rather than
+// smoothly, such as for a clock hand or a typewriter effect.
+func Steps(n int, jump StepJump) Func {
+	if n < 1 {
+		n = 1
+	}
+
+	// JumpNone needs at least two steps to have anywhere to go
+	if jump == JumpNone && n < 2 {
+		n = 2
+	}
+
+	jumps := float64(n)
+	switch jump {
+	case JumpNone:
+		jumps--
+	case JumpBoth:
+		jumps++
+	}
+
+	return func(t float64) float64 {
+		step := math.Floor(t * float64(n))
+
+		if jump == JumpStart || jump == JumpBoth {
+			step++
+		}
+
+		// At the very end we've already made the last jump, so it can't go
+		// any further
+		if t <= 1 && step > jumps {
+			step = jumps
+		}
+
+		return step / jumps
+	}
+}
+
+// BackInWith, BackOutWith and BackInOutWith are the same as BackIn, BackOut
+// and BackInOut but with the amount of overshoot as a parameter, where the
+// fixed versions use 1.70158 for an overshoot of 10%.
+func BackInWith(overshoot float64) Func {
+	return func(t float64) float64 {
+		return t * t * ((overshoot+1)*t - overshoot)
+	}
+}
+
+func BackOutWith(overshoot float64) Func {
+	in := BackInWith(overshoot)
+
+	return func(t float64) float64 {
+		return 1 - in(1-t)
+	}
+}
+
+func BackInOutWith(overshoot float64) Func {
+	s := overshoot * 1.525
+
+	return func(t float64) float64 {
+		t *= 2
+
+		if t < 1 {
+			return 0.5 * (t * t * ((s+1)*t - s))
+		}
+
+		t -= 2
+
+		return 0.5 * (t*t*((s+1)*t+s) + 2)
+	}
+}
+
+// elasticShift works out how far along the sine wave an elastic curve
+// starts, where an amplitude of less than 1 isn't enough to reach the end
+// value and is raised to 1.
+func elasticShift(amplitude, period float64) (float64, float64) {
+	if amplitude < 1 {
+		return 1, period / 4
+	}
+
+	return amplitude, period / (2 * math.Pi) * math.Asin(1/amplitude)
+}
+
+// ElasticInWith, ElasticOutWith and ElasticInOutWith are the same as
+// ElasticIn, ElasticOut and ElasticInOut but with the amplitude and period as
+// parameters, where the fixed versions use an amplitude of 1 and a period of
+// 0.3, or 0.45 for ElasticInOut.
+func ElasticInWith(amplitude, period float64) Func {
+	a, s := elasticShift(amplitude, period)
+
+	return func(t float64) float64 {
+		if t == 0 || t == 1 {
+			return t
+		}
+
+		t--
+
+		return -(a * math.Pow(2, 10*t) * math.Sin((t-s)*(2*math.Pi)/period))
+	}
+}
+
+func ElasticOutWith(amplitude, period float64) Func {
+	a, s := elasticShift(amplitude, period)
+
+	return func(t float64) float64 {
+		if t == 0 || t == 1 {
+			return t
+		}
+
+		return a*math.Pow(2, -10*t)*math.Sin((t-s)*(2*math.Pi)/period) + 1
+	}
+}
+
+func ElasticInOutWith(amplitude, period float64) Func {
+	a, s := elasticShift(amplitude, period)
+
+	return func(t float64) float64 {
+		if t == 0 || t == 1 {
+			return t
+		}
+
+		t = t*2 - 1
+
+		if t < 0 {
+			return -0.5 * (a * math.Pow(2, 10*t) * math.Sin((t-s)*(2*math.Pi)/period))
+		}
+
+		return a*math.Pow(2, -10*t)*math.Sin((t-s)*(2*math.Pi)/period)*0.5 + 1
+	}
+}
+
+// Stop is a point on a Piecewise curve, where Easing is used to get from it
+// to the next stop and is linear if it's nil.
+type Stop struct {
+	T      float64
+	Value  float64
+	Easing Func
+}
+
+// Piecewise creates an easing function that goes through each of the stops,
+// which is useful for recreating a curve from an animation tool.
+// Before the first stop and after the last the value is held.
+func Piecewise(stops ...Stop) Func {
+	stops = append([]Stop(nil), stops...)
+
+	sort.SliceStable(stops, func(i, j int) bool {
+		return stops[i].T < stops[j].T
+	})
+
+	return func(t float64) float64 {
+		if len(stops) == 0 {
+			return t
+		}
+
+		if t <= stops[0].T {
+			return stops[0].Value
+		}
+
+		// The index of the first stop after t, which is never the first stop
+		i := sort.Search(len(stops), func(i int) bool {
+			return stops[i].T > t
+		})
+
+		if i == len(stops) {
+			return stops[len(stops)-1].Value
+		}
+
+		from, to := stops[i-1], stops[i]
+
+		easing := from.Easing
+		if easing == nil {
+			easing = Linear
+		}
+
+		return To((t-from.T)/(to.T-from.T), from.Value, to.Value, easing)
+	}
+}
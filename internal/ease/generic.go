@@ -0,0 +1,95 @@
+package ease
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// LerpFunc interpolates between from and to, where t is usually between 0
+// and 1 but can go beyond either end with easings that overshoot.
+type LerpFunc[T any] func(from, to T, t float64) T
+
+func LerpFloat(from, to, t float64) float64 {
+	return from + (to-from)*t
+}
+
+func LerpVec2(from, to linalg.Vec2, t float64) linalg.Vec2 {
+	return linalg.New(LerpFloat(from.X, to.X, t), LerpFloat(from.Y, to.Y, t))
+}
+
+func LerpRGBA(from, to color.RGBA, t float64) color.RGBA {
+	channel := func(from, to uint8) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(math.MaxUint8, LerpFloat(float64(from), float64(to), t)))))
+	}
+
+	return color.RGBA{
+		R: channel(from.R, to.R),
+		G: channel(from.G, to.G),
+		B: channel(from.B, to.B),
+		A: channel(from.A, to.A),
+	}
+}
+
+type TweenOfHook[T any] func(t *TweenOf[T])
+
+// TweenOf tweens a value of any type that can be interpolated with a
+// LerpFunc.
+//
+// The embedded Tween goes from 0 to 1 and is what does the timing and
+// easing, so all of its methods work as they do for a plain Tween, and
+// From, To and Value here are the ones for the tweened type.
+// The value is worked out whenever the embedded Tween changes, so it's
+// already up to date by the time any hooks are called.
+type TweenOf[T any] struct {
+	*Tween
+	From   T
+	To     T
+	Value  T
+	lerp   LerpFunc[T]
+	target *T
+}
+
+func NewTweenOf[T any](from, to T, duration time.Duration, easing Func, lerp LerpFunc[T]) *TweenOf[T] {
+	t := &TweenOf[T]{
+		Tween: NewTween(0, 1, duration, easing),
+		From:  from,
+		To:    to,
+		Value: from,
+		lerp:  lerp,
+	}
+
+	t.Tween.onValue = t.apply
+
+	return t
+}
+
+// Bind makes the tween write its value to the target every time it's
+// updated, such as the position of a sprite.
+func (t *TweenOf[T]) Bind(target *T) *TweenOf[T] {
+	t.target = target
+
+	return t
+}
+
+func (t *TweenOf[T]) apply() {
+	t.Value = t.lerp(t.From, t.To, t.Tween.Value)
+
+	if t.target != nil {
+		*t.target = t.Value
+	}
+}
+
+func (t *TweenOf[T]) OnStarted(f TweenOfHook[T]) {
+	t.Tween.OnStarted(func(*Tween) {
+		f(t)
+	})
+}
+
+func (t *TweenOf[T]) OnFinished(f TweenOfHook[T]) {
+	t.Tween.OnFinished(func(*Tween) {
+		f(t)
+	})
+}
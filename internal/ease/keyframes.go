@@ -0,0 +1,292 @@
+package ease
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+var easings = map[string]Func{
+	"Linear":       Linear,
+	"QuadIn":       QuadIn,
+	"QuadOut":      QuadOut,
+	"QuadInOut":    QuadInOut,
+	"CubicIn":      CubicIn,
+	"CubicOut":     CubicOut,
+	"CubicInOut":   CubicInOut,
+	"QuartIn":      QuartIn,
+	"QuartOut":     QuartOut,
+	"QuartInOut":   QuartInOut,
+	"QuintIn":      QuintIn,
+	"QuintOut":     QuintOut,
+	"QuintInOut":   QuintInOut,
+	"SineIn":       SineIn,
+	"SineOut":      SineOut,
+	"SineInOut":    SineInOut,
+	"ExpoIn":       ExpoIn,
+	"ExpoOut":      ExpoOut,
+	"ExpoInOut":    ExpoInOut,
+	"CircIn":       CircIn,
+	"CircOut":      CircOut,
+	"CircInOut":    CircInOut,
+	"ElasticIn":    ElasticIn,
+	"ElasticOut":   ElasticOut,
+	"ElasticInOut": ElasticInOut,
+	"BackIn":       BackIn,
+	"BackOut":      BackOut,
+	"BackInOut":    BackInOut,
+	"BounceIn":     BounceIn,
+	"BounceOut":    BounceOut,
+	"BounceInOut":  BounceInOut,
+}
+
+// RegisterEasing gives an easing function a name so that it can be used by
+// the keyframes of an AnimationCurve, which is how curves made with
+// something like CubicBezier can be used from data files.
+func RegisterEasing(name string, easing Func) {
+	if _, ok := easings[name]; ok {
+		panic(fmt.Sprintf("duplicate easing registration for %q", name))
+	}
+
+	easings[name] = easing
+}
+
+type WrapMode byte
+
+const (
+	WrapClamp WrapMode = iota
+	WrapLoop
+	WrapPingPong
+)
+
+var wrapModeNames = map[WrapMode]string{
+	WrapClamp:    "clamp",
+	WrapLoop:     "loop",
+	WrapPingPong: "pingpong",
+}
+
+func (w WrapMode) MarshalText() ([]byte, error) {
+	name, ok := wrapModeNames[w]
+	if !ok {
+		return nil, fmt.Errorf("unknown wrap mode %d", w)
+	}
+
+	return []byte(name), nil
+}
+
+func (w *WrapMode) UnmarshalText(text []byte) error {
+	for mode, name := range wrapModeNames {
+		if name == string(text) {
+			*w = mode
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown wrap mode %q", text)
+}
+
+// Keyframe is a value at a point in time on an AnimationCurve.
+//
+// The segment between a keyframe and the next one is a cubic Hermite curve
+// that leaves the keyframe with its out tangent and arrives at the next
+// keyframe with that one's in tangent, unless the keyframe has the name of an
+// easing function, in which case that's used instead.
+// Func can be set instead of Easing for an easing function that doesn't have
+// a name, and takes priority over it, but it isn't saved with the curve.
+// Tangents are the rate of change of the value per unit of time, so two
+// keyframes with tangents of 0 ease in and out of each other.
+type Keyframe struct {
+	Time       float64 `json:"time"`
+	Value      float64 `json:"value"`
+	InTangent  float64 `json:"in,omitempty"`
+	OutTangent float64 `json:"out,omitempty"`
+	Easing     string  `json:"easing,omitempty"`
+	Func       Func    `json:"-"`
+}
+
+func (k Keyframe) easing() (Func, bool) {
+	if k.Func != nil {
+		return k.Func, true
+	}
+
+	easing, ok := easings[k.Easing]
+
+	return easing, ok
+}
+
+// AnimationCurve is a value that changes over time by going through a list
+// of keyframes, such as the size of a particle over its lifetime.
+//
+// Curves can be authored as JSON in the form:
+//
+//	{"wrap": "loop", "keys": [{"time": 0, "value": 0, "easing": "QuadOut"}, {"time": 1, "value": 1}]}
+type AnimationCurve struct {
+	Keys []Keyframe `json:"keys"`
+	Wrap WrapMode   `json:"wrap"`
+}
+
+func NewAnimationCurve(wrap WrapMode, keys ...Keyframe) *AnimationCurve {
+	c := &AnimationCurve{Wrap: wrap}
+
+	for _, key := range keys {
+		c.Add(key)
+	}
+
+	return c
+}
+
+// NewAnimationCurveFromFile loads a curve from a JSON file.
+func NewAnimationCurveFromFile(curvePath string) (*AnimationCurve, error) {
+	b, err := os.ReadFile(curvePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var c AnimationCurve
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (c *AnimationCurve) UnmarshalJSON(b []byte) error {
+	// The alias doesn't have any methods, which stops us from ending up back
+	// here when we unmarshal into it
+	type curve AnimationCurve
+
+	var data curve
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	for _, key := range data.Keys {
+		if _, ok := easings[key.Easing]; key.Easing != "" && !ok {
+			return fmt.Errorf("unknown easing %q", key.Easing)
+		}
+	}
+
+	sort.SliceStable(data.Keys, func(i, j int) bool {
+		return data.Keys[i].Time < data.Keys[j].Time
+	})
+
+	*c = AnimationCurve(data)
+
+	return nil
+}
+
+// Add adds a keyframe in order of time, after any keyframes that are at the
+// same time.
+// It panics if the keyframe has the name of an easing function that hasn't
+// been registered.
+func (c *AnimationCurve) Add(key Keyframe) {
+	if _, ok := easings[key.Easing]; key.Easing != "" && !ok {
+		panic(fmt.Sprintf("unknown easing %q", key.Easing))
+	}
+
+	i := sort.Search(len(c.Keys), func(i int) bool {
+		return c.Keys[i].Time > key.Time
+	})
+
+	c.Keys = append(c.Keys, Keyframe{})
+	copy(c.Keys[i+1:], c.Keys[i:])
+	c.Keys[i] = key
+}
+
+// Start and End are the times of the first and last keyframes.
+func (c *AnimationCurve) Start() float64 {
+	if len(c.Keys) == 0 {
+		return 0
+	}
+
+	return c.Keys[0].Time
+}
+
+func (c *AnimationCurve) End() float64 {
+	if len(c.Keys) == 0 {
+		return 0
+	}
+
+	return c.Keys[len(c.Keys)-1].Time
+}
+
+func (c *AnimationCurve) wrap(t float64) float64 {
+	start, length := c.Start(), c.End()-c.Start()
+	if length <= 0 {
+		return start
+	}
+
+	switch c.Wrap {
+	case WrapLoop:
+		t = math.Mod(t-start, length)
+		if t < 0 {
+			t += length
+		}
+
+		return start + t
+	case WrapPingPong:
+		t = math.Mod(t-start, 2*length)
+		if t < 0 {
+			t += 2 * length
+		}
+
+		if t > length {
+			t = 2*length - t
+		}
+
+		return start + t
+	default:
+		return math.Max(start, math.Min(t, c.End()))
+	}
+}
+
+// Evaluate gets the value of the curve at the given time, where times
+// outside of the keyframes are wrapped using the curve's wrap mode.
+func (c *AnimationCurve) Evaluate(t float64) float64 {
+	if len(c.Keys) == 0 {
+		return 0
+	}
+
+	t = c.wrap(t)
+
+	// The index of the first keyframe after t, so the segment we're in starts
+	// at the keyframe before it
+	i := sort.Search(len(c.Keys), func(i int) bool {
+		return c.Keys[i].Time > t
+	})
+
+	if i == 0 {
+		return c.Keys[0].Value
+	}
+
+	if i == len(c.Keys) {
+		return c.Keys[len(c.Keys)-1].Value
+	}
+
+	from, to := c.Keys[i-1], c.Keys[i]
+
+	dt := to.Time - from.Time
+	s := (t - from.Time) / dt
+
+	if easing, ok := from.easing(); ok {
+		return To(s, from.Value, to.Value, easing)
+	}
+
+	s2 := s * s
+	s3 := s2 * s
+
+	h00 := 2*s3 - 3*s2 + 1
+	h10 := s3 - 2*s2 + s
+	h01 := -2*s3 + 3*s2
+	h11 := s3 - s2
+
+	return h00*from.Value + h10*dt*from.OutTangent + h01*to.Value + h11*dt*to.InTangent
+}
+
+// Func lets the curve be used as an easing function.
+func (c *AnimationCurve) Func() Func {
+	return c.Evaluate
+}
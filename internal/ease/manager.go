@@ -0,0 +1,82 @@
+package ease
+
+// Animator is anything that a TweenManager can update, such as a Tween or a
+// TweenOf.
+type Animator interface {
+	Update(delta float64)
+	IsFinished() bool
+}
+
+// TweenManager updates any number of tweens and removes them once they've
+// finished, so tweens can be fired off and forgotten about.
+type TweenManager struct {
+	animators  []Animator
+	isUpdating bool
+}
+
+func NewTweenManager() *TweenManager {
+	return &TweenManager{}
+}
+
+func (m *TweenManager) Add(animator Animator) {
+	m.animators = append(m.animators, animator)
+}
+
+func (m *TweenManager) Remove(animator Animator) {
+	for i, a := range m.animators {
+		if a != animator {
+			continue
+		}
+
+		// Removing from inside a hook only clears the slot, which Update
+		// removes once it's finished going through them
+		if m.isUpdating {
+			m.animators[i] = nil
+		} else {
+			m.animators = append(m.animators[:i], m.animators[i+1:]...)
+		}
+
+		return
+	}
+}
+
+func (m *TweenManager) Clear() {
+	if m.isUpdating {
+		for i := range m.animators {
+			m.animators[i] = nil
+		}
+
+		return
+	}
+
+	m.animators = nil
+}
+
+func (m *TweenManager) Len() int {
+	return len(m.animators)
+}
+
+func (m *TweenManager) Update(delta float64) {
+	m.isUpdating = true
+
+	// Anything added by a hook is appended after n, so it isn't updated until
+	// the next call
+	for i, n := 0, len(m.animators); i < n; i++ {
+		if a := m.animators[i]; a != nil {
+			a.Update(delta)
+		}
+	}
+
+	m.isUpdating = false
+
+	animators := m.animators[:0]
+	for _, a := range m.animators {
+		if a != nil && !a.IsFinished() {
+			animators = append(animators, a)
+		}
+	}
+	for i := len(animators); i < len(m.animators); i++ {
+		m.animators[i] = nil
+	}
+	m.animators = animators
+}
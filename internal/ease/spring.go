@@ -0,0 +1,95 @@
+package ease
+
+import "math"
+
+// SpringCurve creates an easing function that follows a spring being
+// released from 0 towards 1, where frequency is how many times it would
+// oscillate over the tween if it wasn't damped.
+// A damping ratio of 1 is critically damped, which gets there as fast as
+// possible without overshooting, and anything less bounces.
+// The frequency needs to be high enough for the spring to settle before the
+// end of the tween, otherwise it jumps to 1 at the end.
+func SpringCurve(dampingRatio, frequency float64) Func {
+	omega := 2 * math.Pi * frequency
+	zeta := math.Max(0, dampingRatio)
+
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return math.Max(0, math.Min(t, 1))
+		}
+
+		switch {
+		case zeta < 1:
+			damped := omega * math.Sqrt(1-zeta*zeta)
+			decay := math.Exp(-zeta * omega * t)
+
+			return 1 - decay*(math.Cos(damped*t)+zeta*omega/damped*math.Sin(damped*t))
+		case zeta == 1:
+			return 1 - math.Exp(-omega*t)*(1+omega*t)
+		default:
+			root := math.Sqrt(zeta*zeta - 1)
+			r1 := -omega * (zeta - root)
+			r2 := -omega * (zeta + root)
+
+			return 1 - (r2*math.Exp(r1*t)-r1*math.Exp(r2*t))/(r2-r1)
+		}
+	}
+}
+
+// Spring moves a value towards a target as if it were attached to it by a
+// spring, and unlike a tween the target can be changed at any time without
+// the movement jumping, which makes it good for following a moving target.
+type Spring struct {
+	Value        float64
+	Velocity     float64
+	Target       float64
+	Frequency    float64
+	DampingRatio float64
+	Precision    float64
+}
+
+// NewSpring creates a spring where frequency is how many times a second it
+// would oscillate if it wasn't damped, and a damping ratio of 1 is
+// critically damped.
+func NewSpring(value, target, frequency, dampingRatio float64) *Spring {
+	return &Spring{
+		Value:        value,
+		Target:       target,
+		Frequency:    frequency,
+		DampingRatio: dampingRatio,
+		Precision:    0.001,
+	}
+}
+
+func (s *Spring) Update(delta float64) {
+	if s.IsFinished() {
+		s.Value = s.Target
+		s.Velocity = 0
+
+		return
+	}
+
+	omega := 2 * math.Pi * s.Frequency
+	stiffness := omega * omega
+	damping := 2 * s.DampingRatio * omega
+
+	// Stiff springs blow up with large steps, so we split the delta into
+	// small enough steps to stay stable no matter what the frame rate is
+	const maxStep = 1.0 / 240
+
+	for delta > 0 {
+		step := math.Min(delta, maxStep)
+		delta -= step
+
+		acceleration := stiffness*(s.Target-s.Value) - damping*s.Velocity
+
+		s.Velocity += acceleration * step
+		s.Value += s.Velocity * step
+	}
+}
+
+// IsFinished reports whether the spring has come to rest at its target, so
+// it can be given to a TweenManager.
+func (s *Spring) IsFinished() bool {
+	return math.Abs(s.Target-s.Value) < s.Precision && math.Abs(s.Velocity) < s.Precision
+}
@@ -0,0 +1,359 @@
+package ease
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Forever is the repeat count for something that repeats without end.
+// Anything that repeats forever has a duration of math.MaxInt64.
+const Forever = -1
+
+// Seeker is anything that can be placed on a Timeline, which includes Tween,
+// TweenOf and Timeline itself so that timelines can be nested.
+type Seeker interface {
+	Duration() time.Duration
+	Seek(at time.Duration)
+}
+
+type TimelineHook func(t *Timeline)
+
+func seconds(d time.Duration) float64 {
+	if d == math.MaxInt64 {
+		return math.Inf(1)
+	}
+
+	return d.Seconds()
+}
+
+func duration(s float64) time.Duration {
+	if math.IsInf(s, 1) || s >= math.MaxInt64/float64(time.Second) {
+		return math.MaxInt64
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
+
+// playback is how something with a duration of its own is played, which
+// is shared by tracks and timelines.
+type playback struct {
+	delay  float64
+	repeat int
+	isYoyo bool
+}
+
+func (p playback) length(d float64) float64 {
+	if p.repeat < 0 || math.IsInf(d, 1) {
+		return math.Inf(1)
+	}
+
+	return p.delay + d*float64(p.repeat+1)
+}
+
+// at converts a time from the start of the playback into a time within a
+// single pass of something that takes d seconds.
+func (p playback) at(local, d float64) float64 {
+	local -= p.delay
+
+	if local <= 0 || d <= 0 || math.IsInf(d, 1) {
+		return math.Max(0, math.Min(local, d))
+	}
+
+	// The very end of a pass is the end of that pass rather than the start
+	// of the next one, otherwise finishing would snap back to the start
+	cycle := math.Ceil(local/d) - 1
+	pos := local - cycle*d
+
+	if p.repeat >= 0 && cycle > float64(p.repeat) {
+		cycle = float64(p.repeat)
+		pos = d
+	}
+
+	if p.isYoyo && math.Mod(cycle, 2) == 1 {
+		pos = d - pos
+	}
+
+	return pos
+}
+
+// position is a time on a timeline that's worked out when it's needed, so
+// changing the delay or repeat count of a track moves everything after it.
+type position struct {
+	anchor *Track
+	isEnd  bool
+	at     float64
+}
+
+func (p position) resolve() float64 {
+	if p.anchor == nil {
+		return p.at
+	}
+
+	t := p.anchor.start()
+	if p.isEnd {
+		t += p.anchor.length()
+	}
+
+	return t + p.at
+}
+
+// Track is something placed on a Timeline along with how it's played.
+type Track struct {
+	playback
+	seeker Seeker
+	pos    position
+}
+
+func (tr *Track) start() float64 {
+	return tr.pos.resolve()
+}
+
+func (tr *Track) length() float64 {
+	return tr.playback.length(seconds(tr.seeker.Duration()))
+}
+
+// Offset moves the track from where it would otherwise start, where a
+// negative offset can be used to overlap it with the track before it.
+func (tr *Track) Offset(offset time.Duration) *Track {
+	tr.pos.at += offset.Seconds()
+
+	return tr
+}
+
+// Delay waits before playing the track, which pushes back any tracks that
+// come after it too.
+func (tr *Track) Delay(delay time.Duration) *Track {
+	tr.delay = delay.Seconds()
+
+	return tr
+}
+
+// Repeat sets how many more times the track plays after the first, which
+// can be Forever.
+func (tr *Track) Repeat(count int) *Track {
+	tr.repeat = count
+
+	return tr
+}
+
+// Yoyo makes every other repeat of the track play backwards.
+func (tr *Track) Yoyo(isYoyo bool) *Track {
+	tr.isYoyo = isYoyo
+
+	return tr
+}
+
+func (tr *Track) seek(local float64) {
+	tr.seeker.Seek(duration(tr.at(local, seconds(tr.seeker.Duration()))))
+}
+
+// Timeline plays tweens, and anything else that can be seeked, one after the
+// other or at the same time.
+//
+// Tweens on a timeline are driven by seeking them, so their own hooks
+// aren't called and they shouldn't be updated anywhere else.
+type Timeline struct {
+	playback
+	tracks        []*Track
+	labels        map[string]position
+	elapsed       float64
+	previous      float64
+	isRendered    bool
+	isPaused      bool
+	isFinished    bool
+	finishedFuncs []TimelineHook
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{
+		labels: make(map[string]position),
+	}
+}
+
+func (t *Timeline) last() *Track {
+	if len(t.tracks) == 0 {
+		return nil
+	}
+
+	return t.tracks[len(t.tracks)-1]
+}
+
+func (t *Timeline) add(seeker Seeker, pos position) *Track {
+	track := &Track{seeker: seeker, pos: pos}
+
+	t.tracks = append(t.tracks, track)
+
+	return track
+}
+
+// Then adds a track that starts once the last track added has finished.
+func (t *Timeline) Then(seeker Seeker) *Track {
+	return t.add(seeker, position{anchor: t.last(), isEnd: true})
+}
+
+// With adds a track that starts at the same time as the last track added.
+func (t *Timeline) With(seeker Seeker) *Track {
+	return t.add(seeker, position{anchor: t.last()})
+}
+
+// At adds a track that starts at the given time from the start of the
+// timeline.
+func (t *Timeline) At(seeker Seeker, at time.Duration) *Track {
+	return t.add(seeker, position{at: at.Seconds()})
+}
+
+// AtLabel adds a track that starts at a label.
+func (t *Timeline) AtLabel(seeker Seeker, name string) *Track {
+	pos, ok := t.labels[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown label %q", name))
+	}
+
+	return t.add(seeker, pos)
+}
+
+// Label names the point where the last track added finishes, which is where
+// a track added with Then would start.
+func (t *Timeline) Label(name string) {
+	t.labels[name] = position{anchor: t.last(), isEnd: true}
+}
+
+// LabelAt names the given time from the start of the timeline.
+func (t *Timeline) LabelAt(name string, at time.Duration) {
+	t.labels[name] = position{at: at.Seconds()}
+}
+
+func (t *Timeline) SetRepeat(count int) {
+	t.repeat = count
+}
+
+func (t *Timeline) SetYoyo(isYoyo bool) {
+	t.isYoyo = isYoyo
+}
+
+// content is how long a single pass of the timeline takes.
+func (t *Timeline) content() float64 {
+	var end float64
+	for _, track := range t.tracks {
+		end = math.Max(end, track.start()+track.length())
+	}
+
+	return end
+}
+
+// Duration is how long the timeline takes to play, including its repeats.
+func (t *Timeline) Duration() time.Duration {
+	return duration(t.playback.length(t.content()))
+}
+
+func (t *Timeline) Play() {
+	t.isPaused = false
+}
+
+func (t *Timeline) Pause() {
+	t.isPaused = true
+}
+
+func (t *Timeline) IsFinished() bool {
+	return t.isFinished
+}
+
+func (t *Timeline) Reset() {
+	t.Seek(0)
+}
+
+// Seek moves the timeline to the given time from its start, including its
+// repeats.
+func (t *Timeline) Seek(at time.Duration) {
+	total := t.playback.length(t.content())
+
+	t.elapsed = math.Max(0, math.Min(seconds(at), total))
+	t.isFinished = t.elapsed >= total
+
+	t.render()
+}
+
+// SeekLabel moves the timeline to a label in its first pass and reports
+// whether there was a label with that name.
+func (t *Timeline) SeekLabel(name string) bool {
+	pos, ok := t.labels[name]
+	if ok {
+		t.Seek(duration(pos.resolve()))
+	}
+
+	return ok
+}
+
+func (t *Timeline) Update(delta float64) {
+	if t.isPaused || t.isFinished {
+		return
+	}
+
+	total := t.playback.length(t.content())
+
+	t.elapsed += delta
+
+	if t.elapsed >= total {
+		t.elapsed = total
+		t.isFinished = true
+	}
+
+	t.render()
+
+	if t.isFinished {
+		for _, f := range t.finishedFuncs {
+			f(t)
+		}
+	}
+}
+
+func (t *Timeline) render() {
+	to := t.at(t.elapsed, t.content())
+
+	from := t.previous
+	if !t.isRendered {
+		from = to
+	}
+
+	t.previous = to
+	t.isRendered = true
+
+	// Moving forward, any track that was passed over is left at its end, and
+	// moving backward, any track that was passed over is left at its start
+	// We go through the tracks in the order they were added when moving
+	// forward, so that when tracks overlap the later one wins, and in the
+	// opposite order when moving backward
+	if to >= from {
+		for _, track := range t.tracks {
+			start := track.start()
+			end := start + track.length()
+
+			switch {
+			case to >= start && to <= end:
+				track.seek(to - start)
+			case to > end && from <= end:
+				track.seek(end - start)
+			}
+		}
+
+		return
+	}
+
+	for i := len(t.tracks) - 1; i >= 0; i-- {
+		track := t.tracks[i]
+		start := track.start()
+		end := start + track.length()
+
+		switch {
+		case to >= start && to <= end:
+			track.seek(to - start)
+		case to < start && from >= start:
+			track.seek(0)
+		}
+	}
+}
+
+func (t *Timeline) OnFinished(f TimelineHook) {
+	t.finishedFuncs = append(t.finishedFuncs, f)
+}
@@ -1,6 +1,9 @@
 package ease
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type Direction byte
 
@@ -26,6 +29,10 @@ type Tween struct {
 	isPaused      bool
 	startedFuncs  []TweenHook
 	finishedFuncs []TweenHook
+
+	// onValue is called whenever the value changes, before any hooks, so
+	// that TweenOf can keep its own value in step
+	onValue func()
 }
 
 func NewTween(from, to float64, duration time.Duration, easing Func) *Tween {
@@ -48,6 +55,8 @@ func (t *Tween) Reset() {
 	t.isInverted = false
 	t.isStarted = false
 	t.isFinished = false
+
+	t.changed()
 }
 
 func (t *Tween) Play() {
@@ -58,6 +67,10 @@ func (t *Tween) Pause() {
 	t.isPaused = true
 }
 
+func (t *Tween) IsFinished() bool {
+	return t.isFinished
+}
+
 func (t *Tween) Update(delta float64) {
 	if t.isPaused || t.isFinished {
 		return
@@ -93,6 +106,8 @@ func (t *Tween) Update(delta float64) {
 		t.Value = To(t.elapsed/t.duration, t.From, t.To, t.easing)
 	}
 
+	t.changed()
+
 	if t.isFinished {
 		t.isStarted = false
 
@@ -102,6 +117,30 @@ func (t *Tween) Update(delta float64) {
 	}
 }
 
+// Seek moves the tween to the given time from its start, as if it was
+// playing forward, without calling any hooks.
+func (t *Tween) Seek(at time.Duration) {
+	t.elapsed = math.Max(0, math.Min(at.Seconds(), t.duration))
+	t.isReversed = false
+	t.isInverted = false
+	t.isFinished = t.elapsed >= t.duration
+
+	progress := 1.0
+	if t.duration > 0 {
+		progress = t.elapsed / t.duration
+	}
+
+	t.Value = To(progress, t.From, t.To, t.easing)
+
+	t.changed()
+}
+
+func (t *Tween) changed() {
+	if t.onValue != nil {
+		t.onValue()
+	}
+}
+
 func (t *Tween) Direction() Direction {
 	if !t.isInverted && !t.isReversed || t.isInverted && t.isReversed {
 		return Forward
@@ -3,21 +3,54 @@ package event
 import (
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 type Event any
 
 type Listener any
 
+// Broker dispatches events to listeners.
+// Apart from Post, none of its methods are safe to call from anything other
+// than the goroutine that owns it.
 type Broker struct {
-	listeners map[string][]Listener
-	queue     []Event
+	topics      map[reflect.Type]topic
+	wildcards   *typedTopic[Event]
+	listeners   map[reflect.Type][]Listener
+	queue       []queued
+	delayed     []*delayed
+	flushCycles int
+	depth       int
+	isFlushing  bool
+	isHandled   bool
+	tracer      *Tracer
+
+	postedMu sync.Mutex
+	posted   []Event
+}
+
+// queued is an event waiting for the next flush.
+// Events queued through the generic Queue function are kept by their topic,
+// so only events queued through Broker.Queue need to be stored here.
+type queued struct {
+	topic topic
+	event Event
 }
 
 func NewBroker() *Broker {
-	return &Broker{listeners: make(map[string][]Listener)}
+	return &Broker{
+		topics:      make(map[reflect.Type]topic),
+		wildcards:   &typedTopic[Event]{},
+		listeners:   make(map[reflect.Type][]Listener),
+		flushCycles: 1,
+	}
 }
 
+// Listen registers a listener using reflection, where the listener must be a
+// function that takes a single event parameter.
+//
+// Listeners registered this way are called through reflection, so for
+// anything that happens often Subscribe should be used instead.
 func (b *Broker) Listen(listener Listener) {
 	listenerType := reflect.TypeOf(listener)
 
@@ -25,42 +58,133 @@ func (b *Broker) Listen(listener Listener) {
 		panic(fmt.Sprintf("listener must have %v parameters, got %v", want, listenerType.NumIn()))
 	}
 
-	key := eventTypeName(listenerType.In(0))
+	key := listenerType.In(0)
 	b.listeners[key] = append(b.listeners[key], listener)
 }
 
+// SetHandled marks the event that's currently being dispatched as handled,
+// which stops it from being passed on to any lower priority listeners.
+// It should only be called from inside a listener.
+func (b *Broker) SetHandled() {
+	b.isHandled = true
+}
+
 func (b *Broker) Dispatch(event Event) {
+	if b.tracer != nil {
+		key := reflect.TypeOf(event)
+		b.tracer.record(TraceDispatch, key, event, b.listenerCount(key), b.depth)
+	}
+
 	b.fire(event)
 }
 
 func (b *Broker) Queue(event Event) {
-	b.queue = append(b.queue, event)
+	if b.tracer != nil {
+		key := reflect.TypeOf(event)
+		b.tracer.record(TraceQueue, key, event, b.listenerCount(key), b.depth)
+	}
+
+	b.queue = append(b.queue, queued{event: event})
+}
+
+// SetFlushCycles sets how many times a flush will go back and dispatch the
+// events that were queued by listeners during that same flush.
+// The default of 1 leaves those events until the next flush, and the limit
+// stops listeners that keep queueing events from locking up the frame.
+func (b *Broker) SetFlushCycles(cycles int) {
+	if cycles < 1 {
+		cycles = 1
+	}
+
+	b.flushCycles = cycles
 }
 
 func (b *Broker) Flush() {
-	for _, event := range b.queue {
-		b.fire(event)
+	// A listener calling Flush would end up dispatching events from the middle
+	// of the queue we're already working through, so it's ignored instead
+	if b.isFlushing {
+		return
+	}
+
+	b.isFlushing = true
+
+	defer func() {
+		b.isFlushing = false
+	}()
+
+	b.takePosted()
+
+	if b.tracer != nil {
+		b.tracer.record(TraceFlush, nil, nil, 0, b.depth)
 	}
 
-	b.queue = b.queue[:0]
+	for cycle := 0; cycle < b.flushCycles && len(b.queue) > 0; cycle++ {
+		// Only the events that were queued before this cycle started are
+		// dispatched, anything queued by the listeners has to wait until the
+		// next cycle or flush
+		n := len(b.queue)
+
+		for i := 0; i < n; i++ {
+			q := b.queue[i]
+			b.queue[i] = queued{}
+
+			if q.topic != nil {
+				q.topic.flushOne(b)
+			} else {
+				if b.tracer != nil {
+					key := reflect.TypeOf(q.event)
+					b.tracer.record(TraceDeliver, key, q.event, b.listenerCount(key), b.depth)
+				}
+
+				b.fire(q.event)
+			}
+		}
+
+		b.queue = b.queue[:copy(b.queue, b.queue[n:])]
+	}
 }
 
 func (b *Broker) fire(event Event) {
-	key := eventTypeName(reflect.TypeOf(event))
-	callArgs := []reflect.Value{reflect.ValueOf(event)}
+	b.depth++
 
-	for _, listenerFunc := range b.listeners[key] {
-		listener := reflect.ValueOf(listenerFunc)
-		listener.Call(callArgs)
+	defer func() {
+		b.depth--
+	}()
+
+	key := reflect.TypeOf(event)
+
+	b.wildcards.publish(b, event)
+
+	if t := b.topics[key]; t != nil && t.publishAny(b, event) {
+		return
 	}
+
+	b.fireReflected(key, event)
 }
 
-func eventTypeName(typ reflect.Type) string {
-	var name string
-	if typ.Kind() == reflect.Ptr {
-		typ = typ.Elem()
-		name = "*"
+func (b *Broker) fireReflected(key reflect.Type, event Event) {
+	listeners := b.listeners[key]
+	if len(listeners) == 0 {
+		return
 	}
 
-	return name + typ.PkgPath() + "." + typ.Name()
+	wasHandled := b.isHandled
+	b.isHandled = false
+
+	defer func() {
+		b.isHandled = wasHandled
+	}()
+
+	callArgs := []reflect.Value{reflect.ValueOf(event)}
+
+	// Listeners registered through reflection don't have priorities, so they
+	// always come after the subscribed ones
+	for _, listenerFunc := range listeners {
+		listener := reflect.ValueOf(listenerFunc)
+		listener.Call(callArgs)
+
+		if b.isHandled {
+			return
+		}
+	}
 }
@@ -0,0 +1,97 @@
+package event
+
+import "testing"
+
+type benchEvent struct {
+	n int
+}
+
+var sink int
+
+func TestPublishDoesNotAllocate(t *testing.T) {
+	broker := NewBroker()
+	Subscribe(broker, func(e benchEvent) {
+		sink += e.n
+	})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Publish(broker, benchEvent{n: 1})
+	})
+	if allocs != 0 {
+		t.Errorf("Publish allocated %v times per event, want 0", allocs)
+	}
+}
+
+func TestQueueDoesNotAllocate(t *testing.T) {
+	broker := NewBroker()
+	Subscribe(broker, func(e benchEvent) {
+		sink += e.n
+	})
+
+	// The first run grows the queues, after which they're reused
+	allocs := testing.AllocsPerRun(100, func() {
+		Queue(broker, benchEvent{n: 1})
+		broker.Flush()
+	})
+	if allocs != 0 {
+		t.Errorf("Queue and Flush allocated %v times per event, want 0", allocs)
+	}
+}
+
+func BenchmarkPublish(b *testing.B) {
+	broker := NewBroker()
+	Subscribe(broker, func(e benchEvent) {
+		sink += e.n
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Publish(broker, benchEvent{n: i})
+	}
+}
+
+func BenchmarkDispatchReflect(b *testing.B) {
+	broker := NewBroker()
+	broker.Listen(func(e benchEvent) {
+		sink += e.n
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		broker.Dispatch(benchEvent{n: i})
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	broker := NewBroker()
+	Subscribe(broker, func(e benchEvent) {
+		sink += e.n
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Queue(broker, benchEvent{n: i})
+		broker.Flush()
+	}
+}
+
+func BenchmarkQueueReflect(b *testing.B) {
+	broker := NewBroker()
+	broker.Listen(func(e benchEvent) {
+		sink += e.n
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		broker.Queue(benchEvent{n: i})
+		broker.Flush()
+	}
+}
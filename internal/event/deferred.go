@@ -0,0 +1,89 @@
+package event
+
+import (
+	"reflect"
+	"time"
+)
+
+type delayed struct {
+	seconds  float64
+	frames   int
+	isFrames bool
+	enqueue  func()
+}
+
+// QueueAfter queues the event once the given amount of time has passed,
+// as measured by the deltas passed to Broker.Update.
+func QueueAfter[T any](b *Broker, event T, delay time.Duration) {
+	b.delayed = append(b.delayed, &delayed{
+		seconds: delay.Seconds(),
+		enqueue: func() {
+			Queue(b, event)
+		},
+	})
+}
+
+// QueueAfterFrames queues the event once Broker.Update has been called the
+// given number of times.
+func QueueAfterFrames[T any](b *Broker, event T, frames int) {
+	b.delayed = append(b.delayed, &delayed{
+		frames:   frames,
+		isFrames: true,
+		enqueue: func() {
+			Queue(b, event)
+		},
+	})
+}
+
+// Update moves any delayed events that are due into the queue, in the order
+// they were scheduled, so they're dispatched by the next call to Flush.
+// It should be called once per frame.
+func (b *Broker) Update(delta float64) {
+	if b.tracer != nil {
+		b.tracer.frame++
+	}
+
+	delayed := b.delayed[:0]
+	for _, d := range b.delayed {
+		d.seconds -= delta
+		d.frames--
+
+		if d.isFrames && d.frames <= 0 || !d.isFrames && d.seconds <= 0 {
+			d.enqueue()
+		} else {
+			delayed = append(delayed, d)
+		}
+	}
+
+	for i := len(delayed); i < len(b.delayed); i++ {
+		b.delayed[i] = nil
+	}
+
+	b.delayed = delayed
+}
+
+// Post queues an event from any goroutine, such as an asset loader or the
+// network, so that it's dispatched on the broker's own goroutine during the
+// next call to Flush.
+func (b *Broker) Post(event Event) {
+	b.postedMu.Lock()
+	b.posted = append(b.posted, event)
+	b.postedMu.Unlock()
+}
+
+func (b *Broker) takePosted() {
+	b.postedMu.Lock()
+	defer b.postedMu.Unlock()
+
+	for i, event := range b.posted {
+		if b.tracer != nil {
+			key := reflect.TypeOf(event)
+			b.tracer.record(TraceQueue, key, event, b.listenerCount(key), b.depth)
+		}
+
+		b.queue = append(b.queue, queued{event: event})
+		b.posted[i] = nil
+	}
+
+	b.posted = b.posted[:0]
+}
@@ -0,0 +1,279 @@
+package event
+
+import "reflect"
+
+// Subscription is returned when subscribing to a type of event and can be
+// used to stop receiving those events.
+type Subscription struct {
+	unsubscribe func()
+}
+
+// Unsubscribe stops the listener from receiving any more events, including
+// any events that are in the middle of being dispatched.
+// It's safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+		s.unsubscribe = nil
+	}
+}
+
+type Option func(o *options)
+
+type options struct {
+	priority int
+	isOnce   bool
+}
+
+// Priority sets the priority of a listener, where listeners with a higher
+// priority are called first.
+// Listeners with the same priority are called in the order they subscribed,
+// and the default priority is 0.
+func Priority(priority int) Option {
+	return func(o *options) {
+		o.priority = priority
+	}
+}
+
+// Once unsubscribes the listener after the first event it receives.
+func Once() Option {
+	return func(o *options) {
+		o.isOnce = true
+	}
+}
+
+type topic interface {
+	publishAny(b *Broker, event Event) bool
+	flushOne(b *Broker)
+	count() int
+}
+
+type subscriber[T any] struct {
+	f         func(T)
+	filter    func(T) bool
+	priority  int
+	isOnce    bool
+	isRemoved bool
+}
+
+// typedTopic holds the subscribers for a single type of event along with the
+// events of that type that are waiting to be flushed.
+type typedTopic[T any] struct {
+	subscribers []*subscriber[T]
+	queue       []T
+	head        int
+}
+
+// publish calls each of the subscribers in priority order and reports whether
+// one of them marked the event as handled.
+func (t *typedTopic[T]) publish(b *Broker, event T) bool {
+	// Each dispatch gets its own handled flag so that a listener dispatching
+	// another event doesn't affect the one it's handling
+	wasHandled := b.isHandled
+	b.isHandled = false
+
+	defer func() {
+		b.isHandled = wasHandled
+	}()
+
+	// The slice is replaced rather than modified when a subscriber is removed,
+	// so ranging over it is safe even if a listener unsubscribes
+	for _, s := range t.subscribers {
+		if s.isRemoved {
+			continue
+		}
+
+		if s.filter != nil && !s.filter(event) {
+			continue
+		}
+
+		if s.isOnce {
+			t.remove(s)
+		}
+
+		s.f(event)
+
+		if b.isHandled {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *typedTopic[T]) publishAny(b *Broker, event Event) bool {
+	return t.publish(b, event.(T))
+}
+
+func (t *typedTopic[T]) count() int {
+	return len(t.subscribers)
+}
+
+func (t *typedTopic[T]) flushOne(b *Broker) {
+	var zero T
+
+	event := t.queue[t.head]
+	t.queue[t.head] = zero
+	t.head++
+
+	if t.head == len(t.queue) {
+		t.queue = t.queue[:0]
+		t.head = 0
+	}
+
+	if b.tracer != nil {
+		key := typeOf[T]()
+		b.tracer.record(TraceDeliver, key, event, b.listenerCount(key), b.depth)
+	}
+
+	dispatch(b, t, event)
+}
+
+func (t *typedTopic[T]) add(s *subscriber[T]) {
+	// Subscribers are kept sorted by priority so there's no sorting to do when
+	// dispatching, and a new subscriber goes after everything with the same
+	// priority so that registration order is kept
+	i := len(t.subscribers)
+	for i > 0 && t.subscribers[i-1].priority < s.priority {
+		i--
+	}
+
+	subscribers := make([]*subscriber[T], 0, len(t.subscribers)+1)
+	subscribers = append(subscribers, t.subscribers[:i]...)
+	subscribers = append(subscribers, s)
+	subscribers = append(subscribers, t.subscribers[i:]...)
+	t.subscribers = subscribers
+}
+
+func (t *typedTopic[T]) remove(s *subscriber[T]) {
+	if s.isRemoved {
+		return
+	}
+
+	s.isRemoved = true
+
+	subscribers := make([]*subscriber[T], 0, len(t.subscribers))
+	for _, other := range t.subscribers {
+		if other != s {
+			subscribers = append(subscribers, other)
+		}
+	}
+	t.subscribers = subscribers
+}
+
+func typeOf[T any]() reflect.Type {
+	// Getting the type through a nil pointer means we don't need a value of
+	// T, and gives the interface type itself when T is an interface rather
+	// than the type of whatever value it holds
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func topicOf[T any](b *Broker) *typedTopic[T] {
+	key := typeOf[T]()
+
+	t, ok := b.topics[key].(*typedTopic[T])
+	if !ok {
+		t = &typedTopic[T]{}
+		b.topics[key] = t
+	}
+
+	return t
+}
+
+func subscribe[T any](t *typedTopic[T], filter func(event T) bool, f func(event T), opts []Option) *Subscription {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &subscriber[T]{
+		f:        f,
+		filter:   filter,
+		priority: o.priority,
+		isOnce:   o.isOnce,
+	}
+
+	t.add(s)
+
+	return &Subscription{
+		unsubscribe: func() {
+			t.remove(s)
+		},
+	}
+}
+
+// Subscribe registers a listener for events of type T.
+// Unlike Broker.Listen the listener is type checked at compile time and is
+// called directly rather than through reflection.
+//
+// Events are matched on their exact type, so when T is an interface the
+// listener only receives events that are published as that interface type,
+// not every event that implements it.
+func Subscribe[T any](b *Broker, f func(event T), opts ...Option) *Subscription {
+	return subscribe(topicOf[T](b), nil, f, opts)
+}
+
+// SubscribeWhere is the same as Subscribe, but the listener is only called
+// when the predicate returns true, for example to only receive the events
+// for a given entity.
+// An event that's filtered out doesn't count towards Once.
+func SubscribeWhere[T any](b *Broker, predicate func(event T) bool, f func(event T), opts ...Option) *Subscription {
+	return subscribe(topicOf[T](b), predicate, f, opts)
+}
+
+// SubscribeAll registers a listener that receives every event dispatched by
+// the broker, which is mostly useful for logging and debugging.
+// These listeners are called before any of the listeners for the event's
+// type, so they see events even if they end up being handled.
+func SubscribeAll(b *Broker, f func(event Event), opts ...Option) *Subscription {
+	return subscribe(b.wildcards, nil, f, opts)
+}
+
+// Publish immediately dispatches the event to every listener for type T.
+func Publish[T any](b *Broker, event T) {
+	key := typeOf[T]()
+	t, _ := b.topics[key].(*typedTopic[T])
+
+	if b.tracer != nil {
+		b.tracer.record(TraceDispatch, key, event, b.listenerCount(key), b.depth)
+	}
+
+	dispatch(b, t, event)
+}
+
+func dispatch[T any](b *Broker, t *typedTopic[T], event T) {
+	b.depth++
+
+	defer func() {
+		b.depth--
+	}()
+
+	// Only box the event for the wildcard and reflected listeners if there
+	// are any, otherwise we'd be allocating on every publish
+	if len(b.wildcards.subscribers) > 0 {
+		b.wildcards.publish(b, event)
+	}
+
+	if t != nil && t.publish(b, event) {
+		return
+	}
+
+	if key := typeOf[T](); len(b.listeners[key]) > 0 {
+		b.fireReflected(key, event)
+	}
+}
+
+// Queue holds onto the event until the next call to Broker.Flush.
+// Events are dispatched in the order they were queued, no matter whether
+// they were queued with this function or Broker.Queue.
+func Queue[T any](b *Broker, event T) {
+	t := topicOf[T](b)
+	t.queue = append(t.queue, event)
+
+	if b.tracer != nil {
+		key := typeOf[T]()
+		b.tracer.record(TraceQueue, key, event, b.listenerCount(key), b.depth)
+	}
+
+	b.queue = append(b.queue, queued{topic: t})
+}
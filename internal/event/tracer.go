@@ -0,0 +1,257 @@
+package event
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+)
+
+type TraceKind string
+
+const (
+	TraceDispatch TraceKind = "dispatch"
+	TraceQueue    TraceKind = "queue"
+	TraceFlush    TraceKind = "flush"
+	TraceDeliver  TraceKind = "deliver"
+)
+
+// Record is a single thing that happened in a traced broker.
+//
+// TraceFlush records mark the start of a flush and have no event, whereas
+// TraceDeliver records are the queued events being dispatched by that flush.
+// Listeners is the number of listeners that could have received the event
+// at the time, before any filters were applied.
+// Depth is how many events were being dispatched when the record was made,
+// so anything above 0 was done by a listener rather than by the game itself.
+// Frames are counted by calls to Broker.Update.
+type Record struct {
+	Frame     int       `json:"frame"`
+	Time      time.Time `json:"time"`
+	Kind      TraceKind `json:"kind"`
+	Type      string    `json:"type,omitempty"`
+	Depth     int       `json:"depth,omitempty"`
+	Listeners int       `json:"listeners"`
+	Event     Event     `json:"event,omitempty"`
+}
+
+// Tracer records what a broker does so it can be inspected, saved and
+// replayed, and is attached with Broker.SetTracer.
+// Once the limit is reached the oldest records are thrown away, and a limit
+// of 0 keeps everything.
+type Tracer struct {
+	records []Record
+	start   int
+	limit   int
+	frame   int
+}
+
+func NewTracer(limit int) *Tracer {
+	return &Tracer{limit: limit}
+}
+
+// SetTracer attaches a tracer to the broker, or detaches it if nil.
+// Nothing is recorded, and there's no cost, without one.
+func (b *Broker) SetTracer(t *Tracer) {
+	b.tracer = t
+}
+
+func (t *Tracer) Frame() int {
+	return t.frame
+}
+
+func (t *Tracer) Len() int {
+	return len(t.records)
+}
+
+// At returns the record at index i, where 0 is the oldest.
+func (t *Tracer) At(i int) Record {
+	return t.records[(t.start+i)%len(t.records)]
+}
+
+// Records returns a copy of the records from oldest to newest.
+func (t *Tracer) Records() []Record {
+	records := make([]Record, t.Len())
+	for i := range records {
+		records[i] = t.At(i)
+	}
+
+	return records
+}
+
+func (t *Tracer) Clear() {
+	t.records = t.records[:0]
+	t.start = 0
+}
+
+func (t *Tracer) record(kind TraceKind, key reflect.Type, event Event, listeners, depth int) {
+	r := Record{
+		Frame:     t.frame,
+		Time:      time.Now(),
+		Kind:      kind,
+		Depth:     depth,
+		Listeners: listeners,
+		Event:     event,
+	}
+
+	if key != nil {
+		r.Type = eventTypeName(key)
+	}
+
+	// Once we're at the limit the records are used as a ring buffer, with the
+	// oldest record being overwritten each time
+	if t.limit <= 0 || len(t.records) < t.limit {
+		t.records = append(t.records, r)
+
+		return
+	}
+
+	t.records[t.start] = r
+	t.start = (t.start + 1) % len(t.records)
+}
+
+// WriteJSONLines writes each record as a line of JSON, oldest first.
+func (t *Tracer) WriteJSONLines(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	for i := 0; i < t.Len(); i++ {
+		if err := encoder.Encode(t.At(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replay dispatches, queues and flushes the recorded events on the given
+// broker in the same order they were originally, without going through any
+// serialisation.
+//
+// Only what was done outside of the listeners is replayed, because anything
+// a listener did is done again when its event is replayed to the same
+// listeners.
+func (t *Tracer) Replay(b *Broker) {
+	for i := 0; i < t.Len(); i++ {
+		r := t.At(i)
+		if r.Depth > 0 {
+			continue
+		}
+
+		switch r.Kind {
+		case TraceDispatch:
+			b.Dispatch(r.Event)
+		case TraceQueue:
+			b.Queue(r.Event)
+		case TraceFlush:
+			b.Flush()
+		}
+	}
+}
+
+// eventTypeName includes the full package path so that types with the same
+// name in different packages can be told apart in a log.
+func eventTypeName(typ reflect.Type) string {
+	var name string
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+		name = "*"
+	}
+
+	// Unnamed types, such as slices and maps, have no package path to add
+	if typ.Name() == "" {
+		return name + typ.String()
+	}
+
+	return name + typ.PkgPath() + "." + typ.Name()
+}
+
+func (b *Broker) listenerCount(key reflect.Type) int {
+	n := len(b.wildcards.subscribers) + len(b.listeners[key])
+	if t := b.topics[key]; t != nil {
+		n += t.count()
+	}
+
+	return n
+}
+
+// Replayer reads event logs that were written with Tracer.WriteJSONLines
+// back into a broker.
+// Every type of event in the log has to be registered with RegisterReplay so
+// that it can be decoded into the right type.
+type Replayer struct {
+	decoders map[string]func(data json.RawMessage) (Event, error)
+}
+
+func NewReplayer() *Replayer {
+	return &Replayer{decoders: make(map[string]func(data json.RawMessage) (Event, error))}
+}
+
+func RegisterReplay[T any](r *Replayer) {
+	r.decoders[eventTypeName(typeOf[T]())] = func(data json.RawMessage) (Event, error) {
+		var event T
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, err
+		}
+
+		return event, nil
+	}
+}
+
+// Replay reads the log and replays it into the broker in the same way as
+// Tracer.Replay.
+func (r *Replayer) Replay(b *Broker, rd io.Reader) error {
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 1024*1024)
+
+	var line struct {
+		Kind  TraceKind       `json:"kind"`
+		Type  string          `json:"type"`
+		Depth int             `json:"depth"`
+		Event json.RawMessage `json:"event"`
+	}
+
+	for n := 1; scanner.Scan(); n++ {
+		line.Kind = ""
+		line.Type = ""
+		line.Depth = 0
+		line.Event = nil
+
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("line %v: %w", n, err)
+		}
+
+		if line.Depth > 0 {
+			continue
+		}
+
+		if line.Kind == TraceFlush {
+			b.Flush()
+
+			continue
+		}
+
+		if line.Kind != TraceDispatch && line.Kind != TraceQueue {
+			continue
+		}
+
+		decode, ok := r.decoders[line.Type]
+		if !ok {
+			return fmt.Errorf("line %v: unregistered event type %q", n, line.Type)
+		}
+
+		event, err := decode(line.Event)
+		if err != nil {
+			return fmt.Errorf("line %v: %w", n, err)
+		}
+
+		if line.Kind == TraceDispatch {
+			b.Dispatch(event)
+		} else {
+			b.Queue(event)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"unsafe"
 
+	"github.com/robotscone/adventure/internal/linalg"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -25,19 +26,9 @@ const (
 	ScaleAnisotropic ScaleQuality = "best"
 )
 
-type Rect struct {
-	X      int
-	Y      int
-	Width  int
-	Height int
-}
+type Rect = linalg.Rect[int]
 
-type FRect struct {
-	X      float64
-	Y      float64
-	Width  float64
-	Height float64
-}
+type FRect = linalg.Rect[float64]
 
 type Renderer struct{ *sdl.Renderer }
 
@@ -105,6 +96,23 @@ func (rn *Renderer) NewTexture(img image.Image, scaleQuality ScaleQuality) *Text
 	return t
 }
 
+// LoadImage reads and decodes an image file in the same way as
+// NewTextureFromFile, but returns any errors, for anything else that needs
+// the pixels of an image, such as a mouse cursor.
+func LoadImage(imagePath string) (image.Image, error) {
+	b, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
 func (rn *Renderer) NewTextureFromFile(imagePath string, scaleQuality ScaleQuality) *Texture {
 	if t := textureCache[scaleQuality][imagePath]; t != nil {
 		return t
@@ -121,6 +129,7 @@ func (rn *Renderer) NewTextureFromFile(imagePath string, scaleQuality ScaleQuali
 	}
 
 	t := rn.NewTexture(img, scaleQuality)
+	t.path = imagePath
 
 	textureCache[scaleQuality][imagePath] = t
 
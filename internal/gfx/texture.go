@@ -9,6 +9,7 @@ import (
 type Texture struct {
 	renderer *Renderer
 	texture  *sdl.Texture
+	path     string
 	width    int
 	height   int
 }
@@ -17,6 +18,12 @@ func (t *Texture) Renderer() *Renderer {
 	return t.renderer
 }
 
+// Path is the file the texture was loaded from, which is empty for a
+// texture that wasn't loaded with NewTextureFromFile.
+func (t *Texture) Path() string {
+	return t.path
+}
+
 func (t *Texture) Width() int {
 	return t.width
 }
@@ -6,6 +6,7 @@ type TileMap struct {
 	width      int
 	height     int
 	tiles      [][]*Sprite
+	solid      [][]bool
 }
 
 func NewTileMap(tileWidth, tileHeight int) *TileMap {
@@ -15,7 +16,7 @@ func NewTileMap(tileWidth, tileHeight int) *TileMap {
 	}
 }
 
-func (tm *TileMap) SetTile(x, y int, sprite *Sprite) {
+func (tm *TileMap) grow(x, y int) {
 	if tm.width <= x {
 		tm.width = x + 1
 	}
@@ -26,17 +27,45 @@ func (tm *TileMap) SetTile(x, y int, sprite *Sprite) {
 
 	for len(tm.tiles) < tm.height {
 		tm.tiles = append(tm.tiles, make([]*Sprite, tm.width))
+		tm.solid = append(tm.solid, make([]bool, tm.width))
 	}
 
 	for y := range tm.tiles {
 		for len(tm.tiles[y]) < tm.width {
 			tm.tiles[y] = append(tm.tiles[y], nil)
+			tm.solid[y] = append(tm.solid[y], false)
 		}
 	}
+}
+
+func (tm *TileMap) SetTile(x, y int, sprite *Sprite) {
+	tm.grow(x, y)
 
 	tm.tiles[y][x] = sprite
 }
 
+// SetSolid marks whether a tile blocks movement, which doesn't need there to
+// be a sprite in it so that invisible walls can be made.
+func (tm *TileMap) SetSolid(x, y int, isSolid bool) {
+	tm.grow(x, y)
+
+	tm.solid[y][x] = isSolid
+}
+
+// IsSolid reports whether a tile blocks movement, where anything outside of
+// the map doesn't.
+func (tm *TileMap) IsSolid(x, y int) bool {
+	if x < 0 || y < 0 || x >= tm.width || y >= tm.height {
+		return false
+	}
+
+	return tm.solid[y][x]
+}
+
+func (tm *TileMap) TileSize() (int, int) {
+	return tm.tileWidth, tm.tileHeight
+}
+
 func (tm *TileMap) Draw() {
 	for y, row := range tm.tiles {
 		for x, tile := range row {
@@ -0,0 +1,14 @@
+package gfx
+
+import "github.com/robotscone/adventure/internal/ease"
+
+// LerpFRect interpolates every field of the rectangles, which is what
+// ease.NewTweenOf needs to tween an FRect.
+func LerpFRect(from, to FRect, t float64) FRect {
+	return FRect{
+		X:      ease.LerpFloat(from.X, to.X, t),
+		Y:      ease.LerpFloat(from.Y, to.Y, t),
+		Width:  ease.LerpFloat(from.Width, to.Width, t),
+		Height: ease.LerpFloat(from.Height, to.Height, t),
+	}
+}
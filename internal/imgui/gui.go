@@ -1,5 +1,7 @@
 package imgui
 
+import "github.com/robotscone/adventure/internal/linalg"
+
 type Position byte
 
 const (
@@ -41,12 +43,7 @@ type Widget struct {
 		y int
 	}
 
-	clip struct {
-		x      int
-		y      int
-		width  int
-		height int
-	}
+	clip linalg.Rect[int]
 }
 
 func (w *Widget) absX() int {
@@ -89,12 +86,7 @@ type DrawCmd struct {
 	IsFocus  bool
 	Data     any
 
-	Clip struct {
-		X      int
-		Y      int
-		Width  int
-		Height int
-	}
+	Clip linalg.Rect[int]
 }
 
 type DrawFunc func(cmd *DrawCmd)
@@ -305,38 +297,14 @@ func (ui *IMGUI) newWidget(kind WidgetKind, x, y, width, height int) *Widget {
 	w.height = height
 	w.offset.x = 0
 	w.offset.y = 0
-	w.clip.x = w.x
-	w.clip.y = w.y
-	w.clip.width = w.width
-	w.clip.height = w.height
+	w.clip = linalg.Rect[int]{X: w.x, Y: w.y, Width: w.width, Height: w.height}
 
 	if len(ui.containers) > 0 {
 		w.parent = ui.containers[len(ui.containers)-1]
 
-		w.clip.x = w.absX()
-		w.clip.y = w.absY()
-		parentX := w.parent.clip.x
-		parentY := w.parent.clip.y
-		parentRight := parentX + w.parent.clip.width
-		parentBottom := parentY + w.parent.clip.height
-
-		if parentX > w.clip.x {
-			w.clip.width += w.clip.x - parentX
-			w.clip.x = parentX
-		}
-
-		if parentY > w.clip.y {
-			w.clip.height += w.clip.y - parentY
-			w.clip.y = parentY
-		}
-
-		if w.clip.x+w.clip.width > parentRight {
-			w.clip.width = parentRight - w.clip.x
-		}
-
-		if w.clip.y+w.clip.height > parentBottom {
-			w.clip.height = parentBottom - w.clip.y
-		}
+		w.clip.X = w.absX()
+		w.clip.Y = w.absY()
+		w.clip = w.clip.Intersect(w.parent.clip)
 	}
 
 	return w
@@ -347,7 +315,7 @@ func (ui *IMGUI) doButtonLogic(w *Widget) bool {
 		return false
 	}
 
-	if ui.RegionHitRect(w.clip.x, w.clip.y, w.clip.width, w.clip.height) {
+	if ui.RegionHitRect(w.clip.X, w.clip.Y, w.clip.Width, w.clip.Height) {
 		ui.Warm = w
 	}
 
@@ -360,7 +328,9 @@ func (ui *IMGUI) doButtonLogic(w *Widget) bool {
 }
 
 func (ui *IMGUI) RegionHitRect(x, y, width, height int) bool {
-	return ui.Input.Mouse.X >= x && ui.Input.Mouse.X < x+width && ui.Input.Mouse.Y >= y && ui.Input.Mouse.Y < y+height
+	region := linalg.Rect[int]{X: x, Y: y, Width: width, Height: height}
+
+	return region.Contains(ui.Input.Mouse.X, ui.Input.Mouse.Y)
 }
 
 func (ui *IMGUI) DrawData(data any) {
@@ -368,7 +338,7 @@ func (ui *IMGUI) DrawData(data any) {
 }
 
 func (ui *IMGUI) draw(w *Widget, drawCmd *DrawCmd) {
-	if w.clip.width <= 0 || w.clip.height <= 0 {
+	if w.clip.IsEmpty() {
 		return
 	}
 
@@ -377,10 +347,7 @@ func (ui *IMGUI) draw(w *Widget, drawCmd *DrawCmd) {
 	}
 
 	drawCmd.Kind = w.kind
-	drawCmd.Clip.X = w.clip.x
-	drawCmd.Clip.Y = w.clip.y
-	drawCmd.Clip.Width = w.clip.width
-	drawCmd.Clip.Height = w.clip.height
+	drawCmd.Clip = w.clip
 	drawCmd.Data = ui.drawCmdData
 
 	ui.drawCmdData = nil
@@ -0,0 +1,31 @@
+package input
+
+import (
+	"github.com/robotscone/adventure/internal/event"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+var broker *event.Broker
+
+// SetBroker sets the broker that input events are dispatched through.
+// Passing nil stops any events from being dispatched.
+func SetBroker(b *event.Broker) {
+	broker = b
+}
+
+// ControllerConnected is dispatched when a controller is added.
+// Device is nil if there was no device that the controller could be
+// assigned to.
+type ControllerConnected struct {
+	ID     sdl.JoystickID
+	GUID   string
+	Device *Device
+}
+
+// ControllerDisconnected is dispatched when a controller is removed.
+// Device is the device the controller was assigned to, if any.
+type ControllerDisconnected struct {
+	ID     sdl.JoystickID
+	GUID   string
+	Device *Device
+}
@@ -1,6 +1,26 @@
 package input
 
-import "github.com/veandco/go-sdl2/sdl"
+import (
+	"math"
+	"time"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Dead zones are given as a fraction of an axis' full range.
+const (
+	DefaultTriggerDeadZone = 200.0 / math.MaxInt16
+	DefaultStickDeadZone   = 2000.0 / math.MaxInt16
+)
+
+type ControllerType byte
+
+const (
+	ControllerUnknown ControllerType = iota
+	ControllerXbox
+	ControllerPlayStation
+	ControllerSwitch
+)
 
 type controllerButton struct {
 	Button
@@ -13,6 +33,116 @@ func (button *Button) setButtonDeadZone(deadZoneValue float64) {
 	button.deadZone = deadZoneValue
 }
 
+func (c *controller) setDeadZones(trigger, stick float64) {
+	for _, button := range c.current {
+		if !button.isAxis {
+			continue
+		}
+
+		switch button.code {
+		case sdl.CONTROLLER_AXIS_TRIGGERLEFT, sdl.CONTROLLER_AXIS_TRIGGERRIGHT:
+			button.setButtonDeadZone(trigger * math.MaxInt16)
+		default:
+			button.setButtonDeadZone(stick * math.MaxInt16)
+		}
+	}
+}
+
+// SetTriggerDeadZone sets the dead zone of both triggers as a fraction of
+// their full range.
+func (d *Device) SetTriggerDeadZone(value float64) {
+	d.triggerDeadZone = clampDeadZone(value)
+
+	if d.controller != nil {
+		d.controller.setDeadZones(d.triggerDeadZone, d.stickDeadZone)
+	}
+}
+
+func (d *Device) TriggerDeadZone() float64 {
+	return d.triggerDeadZone
+}
+
+// SetStickDeadZone sets the dead zone of both sticks as a fraction of their
+// full range.
+func (d *Device) SetStickDeadZone(value float64) {
+	d.stickDeadZone = clampDeadZone(value)
+
+	if d.controller != nil {
+		d.controller.setDeadZones(d.triggerDeadZone, d.stickDeadZone)
+	}
+}
+
+func (d *Device) StickDeadZone() float64 {
+	return d.stickDeadZone
+}
+
+func clampDeadZone(value float64) float64 {
+	// A dead zone covering the full range would leave nothing to divide by
+	// when the axis value is rescaled, so we stop just short of it
+	return math.Max(0, math.Min(value, 0.99))
+}
+
+func (d *Device) ControllerType() ControllerType {
+	if d.controller == nil {
+		return ControllerUnknown
+	}
+
+	return gameControllerType(d.controller.GameController)
+}
+
+func (d *Device) HasRumble() bool {
+	return d.controller != nil && d.controller.HasRumble()
+}
+
+func (d *Device) HasRumbleTriggers() bool {
+	return d.controller != nil && d.controller.HasRumbleTriggers()
+}
+
+// Rumble starts the controller's low and high frequency motors with
+// intensities in the range [0, 1] for the given duration, replacing any
+// rumble that's already playing.
+// Calling it on a device without a controller does nothing.
+func (d *Device) Rumble(low, high float64, duration time.Duration) error {
+	if d.controller == nil {
+		return nil
+	}
+
+	return d.controller.Rumble(rumbleIntensity(low), rumbleIntensity(high), uint32(duration.Milliseconds()))
+}
+
+// RumbleTriggers is the same as Rumble, but for the motors in the left and
+// right triggers found on some controllers.
+func (d *Device) RumbleTriggers(left, right float64, duration time.Duration) error {
+	if d.controller == nil {
+		return nil
+	}
+
+	return gameControllerRumbleTriggers(d.controller.GameController, rumbleIntensity(left), rumbleIntensity(right), uint32(duration.Milliseconds()))
+}
+
+func (d *Device) StopRumble() error {
+	return d.Rumble(0, 0, 0)
+}
+
+func rumbleIntensity(value float64) uint16 {
+	return uint16(math.MaxUint16 * math.Max(0, math.Min(value, 1)))
+}
+
+// SetLED sets the colour of the controller's light using components in
+// the range [0, 1].
+// Calling it on a device without a controller does nothing.
+func (d *Device) SetLED(r, g, b float64) error {
+	if d.controller == nil {
+		return nil
+	}
+
+	return gameControllerSetLED(d.controller.GameController, ledComponent(r), ledComponent(g), ledComponent(b))
+}
+
+func ledComponent(value float64) uint8 {
+	return uint8(math.MaxUint8 * math.Max(0, math.Min(value, 1)))
+}
+
 func newControllerButtons() map[string]*controllerButton {
 	return map[string]*controllerButton{
 		"a":              {code: sdl.CONTROLLER_BUTTON_A},
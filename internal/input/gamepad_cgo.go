@@ -0,0 +1,92 @@
+package input
+
+// The version of go-sdl2 we depend on doesn't wrap trigger rumble, the
+// controller LED or the controller type, so we call into SDL for those
+// ourselves
+// The functions are stubbed out when building against an SDL version that
+// doesn't have them, in the same way go-sdl2 does for its own functions
+
+//#cgo windows LDFLAGS: -lSDL2
+//#cgo linux freebsd darwin openbsd pkg-config: sdl2
+//
+//#if defined(_WIN32)
+//	#include <SDL2/SDL.h>
+//#else
+//	#include <SDL.h>
+//#endif
+//
+//#if !(SDL_VERSION_ATLEAST(2,0,12))
+//typedef enum
+//{
+//	SDL_CONTROLLER_TYPE_UNKNOWN = 0,
+//	SDL_CONTROLLER_TYPE_XBOX360,
+//	SDL_CONTROLLER_TYPE_XBOXONE,
+//	SDL_CONTROLLER_TYPE_PS3,
+//	SDL_CONTROLLER_TYPE_PS4,
+//	SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_PRO
+//} SDL_GameControllerType;
+//
+//static SDL_GameControllerType SDL_GameControllerGetType(SDL_GameController *gamecontroller)
+//{
+//	return SDL_CONTROLLER_TYPE_UNKNOWN;
+//}
+//#endif
+//
+//#if !(SDL_VERSION_ATLEAST(2,0,14))
+//#define SDL_CONTROLLER_TYPE_PS5 (7)
+//
+//static int SDL_GameControllerRumbleTriggers(SDL_GameController *gamecontroller, Uint16 left_rumble, Uint16 right_rumble, Uint32 duration_ms)
+//{
+//	return -1;
+//}
+//
+//static int SDL_GameControllerSetLED(SDL_GameController *gamecontroller, Uint8 red, Uint8 green, Uint8 blue)
+//{
+//	return -1;
+//}
+//#endif
+//
+//#if !(SDL_VERSION_ATLEAST(2,24,0))
+//#define SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_LEFT (11)
+//#define SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_RIGHT (12)
+//#define SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_PAIR (13)
+//#endif
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func gameControllerRumbleTriggers(ctrl *sdl.GameController, left, right uint16, durationMS uint32) error {
+	if C.SDL_GameControllerRumbleTriggers((*C.SDL_GameController)(unsafe.Pointer(ctrl)), C.Uint16(left), C.Uint16(right), C.Uint32(durationMS)) != 0 {
+		return sdl.GetError()
+	}
+
+	return nil
+}
+
+func gameControllerSetLED(ctrl *sdl.GameController, r, g, b uint8) error {
+	if C.SDL_GameControllerSetLED((*C.SDL_GameController)(unsafe.Pointer(ctrl)), C.Uint8(r), C.Uint8(g), C.Uint8(b)) != 0 {
+		return sdl.GetError()
+	}
+
+	return nil
+}
+
+func gameControllerType(ctrl *sdl.GameController) ControllerType {
+	switch C.SDL_GameControllerGetType((*C.SDL_GameController)(unsafe.Pointer(ctrl))) {
+	case C.SDL_CONTROLLER_TYPE_XBOX360, C.SDL_CONTROLLER_TYPE_XBOXONE:
+		return ControllerXbox
+	case C.SDL_CONTROLLER_TYPE_PS3, C.SDL_CONTROLLER_TYPE_PS4, C.SDL_CONTROLLER_TYPE_PS5:
+		return ControllerPlayStation
+	case C.SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_PRO,
+		C.SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_LEFT,
+		C.SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_RIGHT,
+		C.SDL_CONTROLLER_TYPE_NINTENDO_SWITCH_JOYCON_PAIR:
+		return ControllerSwitch
+	}
+
+	return ControllerUnknown
+}
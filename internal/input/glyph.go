@@ -0,0 +1,103 @@
+package input
+
+import "strings"
+
+// Method is the kind of hardware a device was last used with.
+type Method byte
+
+const (
+	MethodKeyboardMouse Method = iota
+	MethodGamepad
+	MethodTouch
+)
+
+// GlyphMap is a map of input names such as "gamepad:a" or "keyboard:space" to
+// glyph keys, which can be sprite names in an atlas or text to draw with
+// a text.Face.
+type GlyphMap map[string]string
+
+// Glyphs holds the glyph maps used to show button prompts.
+// Gamepad bindings are looked up in the map for the type of controller the
+// device has before falling back to Default, so prompts can match the
+// player's hardware.
+type Glyphs struct {
+	Default     GlyphMap
+	Controllers map[ControllerType]GlyphMap
+}
+
+// Method returns the method that was last used to press one of the device's
+// bound inputs, which is updated by Update.
+func (d *Device) Method() Method {
+	return d.method
+}
+
+// ActiveBinding returns the input name bound to the action that matches the
+// device's current method, falling back to the first binding if there isn't
+// one that matches.
+func (d *Device) ActiveBinding(action string) (string, bool) {
+	names := d.bindings[action]
+	if len(names) == 0 {
+		return "", false
+	}
+
+	for _, name := range names {
+		var method Method
+		switch {
+		case strings.HasPrefix(name, "gamepad:"):
+			method = MethodGamepad
+		case strings.HasPrefix(name, "touch:"):
+			method = MethodTouch
+		default:
+			method = MethodKeyboardMouse
+		}
+
+		if method == d.method {
+			return name, true
+		}
+	}
+
+	return names[0], true
+}
+
+// Glyph returns the glyph key for the action's active binding.
+// If none of the glyph maps have an entry for the binding then a readable
+// label is returned instead, such as "Space" for "keyboard:space".
+func (d *Device) Glyph(action string, glyphs *Glyphs) string {
+	name, ok := d.ActiveBinding(action)
+	if !ok {
+		return ""
+	}
+
+	if glyphs != nil {
+		if strings.HasPrefix(name, "gamepad:") {
+			if glyph, ok := glyphs.Controllers[d.ControllerType()][name]; ok {
+				return glyph
+			}
+		}
+
+		if glyph, ok := glyphs.Default[name]; ok {
+			return glyph
+		}
+	}
+
+	return BindingLabel(name)
+}
+
+// BindingLabel turns an input name into a label that's suitable for showing
+// to the player, for example "gamepad:shoulder:left" becomes "Shoulder Left".
+func BindingLabel(name string) string {
+	parts := strings.Split(name, ":")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/robotscone/adventure/internal/event"
 	"github.com/robotscone/adventure/internal/gfx"
 	"github.com/robotscone/adventure/internal/linalg"
 	"github.com/veandco/go-sdl2/sdl"
@@ -25,24 +26,37 @@ type Button struct {
 
 var Mouse = struct {
 	*Device
-	Position linalg.Vec2
-	Delta    linalg.Vec2
-	current  map[string]*mouseButton
-	previous map[string]*mouseButton
+	Position     linalg.Vec2
+	Delta        linalg.Vec2
+	Wheel        linalg.Vec2
+	IsRelative   bool
+	HasFocus     bool
+	IsInside     bool
+	current      map[string]*mouseButton
+	previous     map[string]*mouseButton
+	pendingWheel linalg.Vec2
+	cursor       *sdl.Cursor
 }{
 	Device: NewDevice(BindingMap{
-		"left":   {"mouse:left"},
-		"middle": {"mouse:middle"},
-		"right":  {"mouse:right"},
-		"extra1": {"mouse:extra1"},
-		"extra2": {"mouse:extra2"},
+		"left":        {"mouse:left"},
+		"middle":      {"mouse:middle"},
+		"right":       {"mouse:right"},
+		"extra1":      {"mouse:extra1"},
+		"extra2":      {"mouse:extra2"},
+		"wheel:up":    {"mouse:wheel:up"},
+		"wheel:down":  {"mouse:wheel:down"},
+		"wheel:left":  {"mouse:wheel:left"},
+		"wheel:right": {"mouse:wheel:right"},
 	}),
+	HasFocus: true,
+	IsInside: true,
 	current:  newMouseButtons(),
 	previous: newMouseButtons(),
 }
 
 type controller struct {
-	id sdl.JoystickID
+	id   sdl.JoystickID
+	guid string
 	*sdl.GameController
 	current  map[string]*controllerButton
 	previous map[string]*controllerButton
@@ -63,10 +77,15 @@ type ButtonMap map[string]*Button
 
 // Device represents an input device and must be created using NewDevice.
 type Device struct {
-	bindings   BindingMap
-	previous   ButtonMap
-	current    ButtonMap
-	controller *controller
+	bindings        BindingMap
+	previous        ButtonMap
+	current         ButtonMap
+	controller      *controller
+	lastGUID        string
+	isAutoAssigned  bool
+	triggerDeadZone float64
+	stickDeadZone   float64
+	method          Method
 }
 
 // Registered devices.
@@ -78,9 +97,12 @@ func NewDevice(bindings BindingMap) *Device {
 	}
 
 	device := &Device{
-		bindings: bindings,
-		previous: make(ButtonMap),
-		current:  make(ButtonMap),
+		bindings:        bindings,
+		previous:        make(ButtonMap),
+		current:         make(ButtonMap),
+		isAutoAssigned:  true,
+		triggerDeadZone: DefaultTriggerDeadZone,
+		stickDeadZone:   DefaultStickDeadZone,
 	}
 
 	for action := range bindings {
@@ -113,6 +135,7 @@ func AddController(id sdl.JoystickID) {
 
 	c := &controller{
 		id:             id,
+		guid:           sdl.JoystickGetGUIDString(device.Joystick().GUID()),
 		GameController: device,
 		current:        newControllerButtons(),
 		previous:       newControllerButtons(),
@@ -120,29 +143,60 @@ func AddController(id sdl.JoystickID) {
 
 	controllers = append(controllers, c)
 
+	assigned := assignController(c)
+
+	if broker != nil {
+		event.Publish(broker, ControllerConnected{ID: id, GUID: c.guid, Device: assigned})
+	}
+}
+
+func assignController(c *controller) *Device {
+	// A device that has previously had a controller with the same GUID gets
+	// it back first, so that a player who unplugs and re-plugs their
+	// controller ends up in the same place they were before
 	for devicenumber, device := range devices {
 		// Device #0 is the mouse, skip over this one.
 		if devicenumber == 0 {
 			continue
 		}
 
-		if device.controller == nil {
-			device.controller = c
-			break
+		if device.controller == nil && device.lastGUID == c.guid {
+			device.assign(c)
+
+			return device
 		}
 	}
+
+	for devicenumber, device := range devices {
+		// Device #0 is the mouse, skip over this one.
+		if devicenumber == 0 {
+			continue
+		}
+
+		if device.controller == nil && device.isAutoAssigned {
+			device.assign(c)
+
+			return device
+		}
+	}
+
+	return nil
 }
 
 func RemoveController(id sdl.JoystickID) {
 	filtered := controllers[:0]
 	for _, controller := range controllers {
 		if controller.id == id {
-			for _, device := range devices {
-				if device.controller == controller {
-					device.controller = nil
+			device := controller.device()
+			if device != nil {
+				// We deliberately keep hold of the last GUID so that the
+				// same controller can be given back to this device if it's
+				// reconnected later on
+				device.controller = nil
+			}
 
-					break
-				}
+			if broker != nil {
+				event.Publish(broker, ControllerDisconnected{ID: id, GUID: controller.guid, Device: device})
 			}
 
 			continue
@@ -153,6 +207,77 @@ func RemoveController(id sdl.JoystickID) {
 	controllers = filtered
 }
 
+func findController(id sdl.JoystickID) *controller {
+	for _, controller := range controllers {
+		if controller.id == id {
+			return controller
+		}
+	}
+
+	return nil
+}
+
+func (c *controller) device() *Device {
+	for _, device := range devices {
+		if device.controller == c {
+			return device
+		}
+	}
+
+	return nil
+}
+
+func (d *Device) assign(c *controller) {
+	// A controller can only ever belong to one device at a time
+	if previous := c.device(); previous != nil && previous != d {
+		previous.controller = nil
+		previous.lastGUID = ""
+	}
+
+	d.controller = c
+	d.lastGUID = c.guid
+
+	c.setDeadZones(d.triggerDeadZone, d.stickDeadZone)
+}
+
+// SetController explicitly assigns the controller with the given instance ID
+// to the device, taking it away from any other device that might have it.
+func (d *Device) SetController(id sdl.JoystickID) bool {
+	c := findController(id)
+	if c == nil {
+		return false
+	}
+
+	d.assign(c)
+
+	return true
+}
+
+// ClearController removes the device's controller and forgets it, so it won't
+// automatically be given back to the device if it's reconnected.
+func (d *Device) ClearController() {
+	d.controller = nil
+	d.lastGUID = ""
+}
+
+func (d *Device) ControllerID() (sdl.JoystickID, bool) {
+	if d.controller == nil {
+		return 0, false
+	}
+
+	return d.controller.id, true
+}
+
+func (d *Device) HasController() bool {
+	return d.controller != nil
+}
+
+// SetAutoAssign controls whether newly connected controllers can be assigned
+// to the device automatically when it doesn't have one.
+func (d *Device) SetAutoAssign(value bool) {
+	d.isAutoAssigned = value
+}
+
 func (d *Device) Get(action string) *Button {
 	if button := d.current[action]; button != nil {
 		return button
@@ -194,12 +319,31 @@ func Update(renderer *gfx.Renderer) {
 		mouseX, mouseY = int32(logicalMouseX), int32(logicalMouseY)
 	}
 
-	mousePreviousX := Mouse.Position.X
-	mousePreviousY := Mouse.Position.Y
-	Mouse.Position.X = float64(mouseX)
-	Mouse.Position.Y = float64(mouseY)
-	Mouse.Delta.X = Mouse.Position.X - mousePreviousX
-	Mouse.Delta.Y = Mouse.Position.Y - mousePreviousY
+	Mouse.Wheel, Mouse.pendingWheel = Mouse.pendingWheel, linalg.Vec2{}
+
+	switch {
+	case !Mouse.HasFocus:
+		// Without focus we don't get told about anything that happens to the
+		// mouse, so rather than risk buttons getting stuck down we treat it as
+		// though it's been left alone
+		Mouse.Delta = linalg.Vec2{}
+		Mouse.Wheel = linalg.Vec2{}
+		mouseState = 0
+	case Mouse.IsRelative:
+		// The cursor is hidden and held in place in relative mode, so the
+		// position stays where it was and only the delta is updated
+		relativeX, relativeY, _ := sdl.GetRelativeMouseState()
+
+		Mouse.Delta.X = float64(relativeX)
+		Mouse.Delta.Y = float64(relativeY)
+	default:
+		mousePreviousX := Mouse.Position.X
+		mousePreviousY := Mouse.Position.Y
+		Mouse.Position.X = float64(mouseX)
+		Mouse.Position.Y = float64(mouseY)
+		Mouse.Delta.X = Mouse.Position.X - mousePreviousX
+		Mouse.Delta.Y = Mouse.Position.Y - mousePreviousY
+	}
 
 	// Save the last mouse state so we can do comparisons
 	for name, button := range Mouse.current {
@@ -208,7 +352,11 @@ func Update(renderer *gfx.Renderer) {
 
 	for name, button := range Mouse.current {
 		var value float64
-		if mouseState&button.mask != 0 {
+		if button.isWheel {
+			// Wheel directions are only ever positive, so the value is the
+			// amount scrolled in the button's direction this frame
+			value = math.Max(0, Mouse.Wheel.Dot(button.direction))
+		} else if mouseState&button.mask != 0 {
 			// If the button is down we set value to 1
 			value = 1
 		}
@@ -217,6 +365,11 @@ func Update(renderer *gfx.Renderer) {
 	}
 
 	for _, controller := range controllers {
+		// Save the last controller state so we can do comparisons
+		for name, button := range controller.current {
+			*controller.previous[name] = *button
+		}
+
 		for name, button := range controller.current {
 			var value float64
 			if button.isAxis {
@@ -255,6 +408,9 @@ func Update(renderer *gfx.Renderer) {
 		setButtonState(&keyboard.current[i], &keyboard.previous[i], float64(value), now)
 	}
 
+	updateText()
+	updateTouch(renderer, now)
+
 	// Loop over all registered devices and update button pointers based
 	// on their internal binding maps
 	for _, device := range devices {
@@ -271,10 +427,19 @@ func Update(renderer *gfx.Renderer) {
 				switch {
 				case strings.HasPrefix(name, "mouse:"):
 					parts := strings.Split(name, ":")
-					key := parts[len(parts)-1]
+					key := strings.Join(parts[1:], ":")
 
-					if button := Mouse.current[key]; button.Value != 0 {
+					button, ok := Mouse.current[key]
+					if !ok {
+						continue
+					}
+
+					if button.Value != 0 {
 						current.Value = button.Value
+
+						if button.IsPressed {
+							device.method = MethodKeyboardMouse
+						}
 					}
 				case strings.HasPrefix(name, "keyboard:"):
 					code, ok := scancodes[name]
@@ -284,6 +449,20 @@ func Update(renderer *gfx.Renderer) {
 
 					if button := &keyboard.current[code]; button.Value != 0 {
 						current.Value = button.Value
+
+						if button.IsPressed {
+							device.method = MethodKeyboardMouse
+						}
+					}
+				case strings.HasPrefix(name, "touch:"):
+					value := Touch.values[strings.TrimPrefix(name, "touch:")]
+
+					if value != 0 {
+						current.Value = value
+
+						if previous.Value == 0 {
+							device.method = MethodTouch
+						}
 					}
 				case strings.HasPrefix(name, "gamepad:"):
 					parts := strings.Split(name, ":")
@@ -299,6 +478,10 @@ func Update(renderer *gfx.Renderer) {
 					}
 					if button.Value != 0 {
 						current.Value = button.Value
+
+						if button.IsPressed {
+							device.method = MethodGamepad
+						}
 					}
 				}
 			}
@@ -1,18 +1,165 @@
 package input
 
-import "github.com/veandco/go-sdl2/sdl"
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	"github.com/robotscone/adventure/internal/gfx"
+	"github.com/robotscone/adventure/internal/linalg"
+	"github.com/veandco/go-sdl2/sdl"
+)
 
 type mouseButton struct {
 	Button
-	mask uint32
+	mask      uint32
+	isWheel   bool
+	direction linalg.Vec2
 }
 
 func newMouseButtons() map[string]*mouseButton {
 	return map[string]*mouseButton{
-		"left":   {mask: sdl.ButtonLMask()},
-		"middle": {mask: sdl.ButtonMMask()},
-		"right":  {mask: sdl.ButtonRMask()},
-		"extra1": {mask: sdl.ButtonX1Mask()},
-		"extra2": {mask: sdl.ButtonX2Mask()},
+		"left":        {mask: sdl.ButtonLMask()},
+		"middle":      {mask: sdl.ButtonMMask()},
+		"right":       {mask: sdl.ButtonRMask()},
+		"extra1":      {mask: sdl.ButtonX1Mask()},
+		"extra2":      {mask: sdl.ButtonX2Mask()},
+		"wheel:up":    {isWheel: true, direction: linalg.New(0, 1)},
+		"wheel:down":  {isWheel: true, direction: linalg.New(0, -1)},
+		"wheel:left":  {isWheel: true, direction: linalg.New(-1, 0)},
+		"wheel:right": {isWheel: true, direction: linalg.New(1, 0)},
+	}
+}
+
+func HandleMouseWheelEvent(event sdl.MouseWheelEvent) {
+	x, y := float64(event.PreciseX), float64(event.PreciseY)
+
+	// The precise values were only added in SDL 2.0.18, so with anything
+	// older we have to make do with whole steps
+	if x == 0 && y == 0 {
+		x, y = float64(event.X), float64(event.Y)
+	}
+
+	// Some platforms have "natural" scrolling where the direction is flipped,
+	// but we always want positive Y to mean scrolling up
+	if event.Direction == sdl.MOUSEWHEEL_FLIPPED {
+		x, y = -x, -y
+	}
+
+	Mouse.pendingWheel.X += x
+	Mouse.pendingWheel.Y += y
+}
+
+func HandleWindowEvent(event sdl.WindowEvent) {
+	switch event.Event {
+	case sdl.WINDOWEVENT_FOCUS_GAINED:
+		Mouse.HasFocus = true
+
+		// Relative mode is dropped by some platforms when focus is lost, so
+		// we make sure it's back the way it was
+		if Mouse.IsRelative {
+			SetRelativeMode(true)
+		}
+	case sdl.WINDOWEVENT_FOCUS_LOST:
+		Mouse.HasFocus = false
+	case sdl.WINDOWEVENT_ENTER:
+		Mouse.IsInside = true
+	case sdl.WINDOWEVENT_LEAVE:
+		Mouse.IsInside = false
+	}
+}
+
+// SetRelativeMode hides the cursor and keeps it within the window so that only
+// Mouse.Delta changes when the mouse is moved, which is what we want
+// for aiming.
+func SetRelativeMode(enabled bool) {
+	sdl.SetRelativeMouseMode(enabled)
+
+	// The relative state accumulates between calls, so we throw away anything
+	// that built up before relative mode was turned on
+	sdl.GetRelativeMouseState()
+
+	Mouse.IsRelative = enabled
+}
+
+func SetCursorVisible(visible bool) {
+	toggle := sdl.DISABLE
+	if visible {
+		toggle = sdl.ENABLE
+	}
+
+	sdl.ShowCursor(toggle)
+}
+
+// SetCursorImage replaces the system cursor with the given image, where hotX
+// and hotY are the point in the image that does the clicking.
+func SetCursorImage(img image.Image, hotX, hotY int) error {
+	bounds := img.Bounds()
+
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, int32(bounds.Dx()), int32(bounds.Dy()), 32, uint32(sdl.PIXELFORMAT_RGBA32))
+	if err != nil {
+		return err
+	}
+	defer surface.Free()
+
+	surface.Lock()
+
+	pixels := surface.Pixels()
+	for y := 0; y < bounds.Dy(); y++ {
+		copy(pixels[y*int(surface.Pitch):], rgba.Pix[y*rgba.Stride:y*rgba.Stride+bounds.Dx()*4])
+	}
+
+	surface.Unlock()
+
+	cursor := sdl.CreateColorCursor(surface, int32(hotX), int32(hotY))
+	if cursor == nil {
+		return sdl.GetError()
+	}
+
+	sdl.SetCursor(cursor)
+
+	if Mouse.cursor != nil {
+		sdl.FreeCursor(Mouse.cursor)
+	}
+
+	Mouse.cursor = cursor
+
+	return nil
+}
+
+// SetCursorFromTexture uses the file a texture was loaded from as the
+// cursor, so a cursor can come from the same source as the rest of the
+// game's textures.
+// SDL cursors are made from pixels in memory rather than a texture on the
+// GPU, and textures don't keep their pixels, so the file is decoded again.
+func SetCursorFromTexture(texture *gfx.Texture, hotX, hotY int) error {
+	if texture.Path() == "" {
+		return errors.New("texture wasn't loaded from a file to make a cursor from")
+	}
+
+	return SetCursorFromFile(texture.Path(), hotX, hotY)
+}
+
+// SetCursorFromFile loads an image with gfx.LoadImage and uses it as the
+// cursor.
+func SetCursorFromFile(imagePath string, hotX, hotY int) error {
+	img, err := gfx.LoadImage(imagePath)
+	if err != nil {
+		return err
+	}
+
+	return SetCursorImage(img, hotX, hotY)
+}
+
+// ResetCursor goes back to the system's default cursor.
+func ResetCursor() {
+	sdl.SetCursor(sdl.GetDefaultCursor())
+
+	if Mouse.cursor != nil {
+		sdl.FreeCursor(Mouse.cursor)
+		Mouse.cursor = nil
 	}
 }
@@ -0,0 +1,199 @@
+package input
+
+const MaxPlayers = 4
+
+type Player struct {
+	*Device
+	Index       int
+	IsJoined    bool
+	IsConnected bool
+
+	// wasConnected is whether the player has ever had a controller since
+	// joining, so that a player who joined on the keyboard isn't reported
+	// as reconnecting when they're given one
+	wasConnected bool
+}
+
+// hasLostController reports whether the player's controller was
+// disconnected, as opposed to them never having had one.
+// The device keeps the GUID of a controller that's removed, but not of one
+// that was cleared.
+func (p *Player) hasLostController() bool {
+	return p.IsJoined && p.controller == nil && p.lastGUID != ""
+}
+
+type PlayerHook func(p *Player)
+
+// PlayerSlots manages a fixed number of local players who join the game by
+// pressing a button on an unassigned controller.
+//
+// The devices that belong to the slots are never assigned controllers
+// automatically, the only way for them to get one is by joining, by being
+// given one explicitly, or by their own controller being reconnected.
+type PlayerSlots struct {
+	players           [MaxPlayers]*Player
+	joinButton        string
+	joinedFuncs       []PlayerHook
+	leftFuncs         []PlayerHook
+	disconnectedFuncs []PlayerHook
+	reconnectedFuncs  []PlayerHook
+}
+
+// NewPlayerSlots creates a device for each player slot using the given
+// bindings, where joinButton is a controller button name such as "start".
+func NewPlayerSlots(bindings BindingMap, joinButton string) *PlayerSlots {
+	ps := &PlayerSlots{joinButton: joinButton}
+
+	for i := range ps.players {
+		device := NewDevice(bindings)
+		device.SetAutoAssign(false)
+
+		ps.players[i] = &Player{
+			Device: device,
+			Index:  i,
+		}
+	}
+
+	return ps
+}
+
+func (ps *PlayerSlots) Player(index int) *Player {
+	if index < 0 || index >= len(ps.players) {
+		return nil
+	}
+
+	return ps.players[index]
+}
+
+func (ps *PlayerSlots) NumJoined() int {
+	var n int
+	for _, p := range ps.players {
+		if p.IsJoined {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Join joins the player in the given slot without a controller, which is
+// useful for a player using the keyboard.
+func (ps *PlayerSlots) Join(index int) *Player {
+	p := ps.Player(index)
+	if p == nil || p.IsJoined {
+		return p
+	}
+
+	p.IsJoined = true
+	p.IsConnected = p.controller != nil
+	p.wasConnected = p.IsConnected
+
+	for _, f := range ps.joinedFuncs {
+		f(p)
+	}
+
+	return p
+}
+
+func (ps *PlayerSlots) Leave(index int) {
+	p := ps.Player(index)
+	if p == nil || !p.IsJoined {
+		return
+	}
+
+	p.ClearController()
+	p.IsJoined = false
+	p.IsConnected = false
+	p.wasConnected = false
+
+	for _, f := range ps.leftFuncs {
+		f(p)
+	}
+}
+
+// Update should be called once per frame after input.Update so that
+// controllers pressing the join button can be given a slot and connection
+// changes can be reported through the hooks.
+func (ps *PlayerSlots) Update() {
+	for _, p := range ps.players {
+		if !p.IsJoined {
+			continue
+		}
+
+		isConnected := p.controller != nil
+		if isConnected == p.IsConnected {
+			continue
+		}
+
+		p.IsConnected = isConnected
+
+		funcs := ps.disconnectedFuncs
+		if isConnected {
+			funcs = nil
+			if p.wasConnected {
+				funcs = ps.reconnectedFuncs
+			}
+
+			p.wasConnected = true
+		}
+
+		for _, f := range funcs {
+			f(p)
+		}
+	}
+
+	for _, c := range controllers {
+		if c.device() != nil {
+			continue
+		}
+
+		button := c.current[ps.joinButton]
+		if button == nil || !button.IsPressed {
+			continue
+		}
+
+		ps.join(c)
+	}
+}
+
+func (ps *PlayerSlots) join(c *controller) {
+	// A player who has lost their controller takes priority over an empty
+	// slot, because the game is most likely paused waiting for them
+	for _, p := range ps.players {
+		if p.hasLostController() {
+			p.assign(c)
+			p.IsConnected = true
+
+			for _, f := range ps.reconnectedFuncs {
+				f(p)
+			}
+
+			return
+		}
+	}
+
+	for _, p := range ps.players {
+		if !p.IsJoined {
+			p.assign(c)
+			ps.Join(p.Index)
+
+			return
+		}
+	}
+}
+
+func (ps *PlayerSlots) OnJoined(f PlayerHook) {
+	ps.joinedFuncs = append(ps.joinedFuncs, f)
+}
+
+func (ps *PlayerSlots) OnLeft(f PlayerHook) {
+	ps.leftFuncs = append(ps.leftFuncs, f)
+}
+
+func (ps *PlayerSlots) OnDisconnected(f PlayerHook) {
+	ps.disconnectedFuncs = append(ps.disconnectedFuncs, f)
+}
+
+func (ps *PlayerSlots) OnReconnected(f PlayerHook) {
+	ps.reconnectedFuncs = append(ps.reconnectedFuncs, f)
+}
@@ -0,0 +1,107 @@
+package input
+
+import "testing"
+
+// newTestController creates a controller without opening one through SDL,
+// which is all the player slots need to assign it.
+func newTestController(guid string) *controller {
+	return &controller{
+		guid:     guid,
+		current:  newControllerButtons(),
+		previous: newControllerButtons(),
+	}
+}
+
+// isolateInput gives the test its own devices and controllers, keeping the
+// mouse as the first device in the same way as the real ones.
+func isolateInput(t *testing.T) {
+	savedDevices, savedControllers := devices, controllers
+
+	devices = devices[:1:1]
+	controllers = nil
+
+	t.Cleanup(func() {
+		devices, controllers = savedDevices, savedControllers
+	})
+}
+
+func TestPlayerSlotsKeyboardThenController(t *testing.T) {
+	isolateInput(t)
+
+	ps := NewPlayerSlots(nil, "start")
+
+	var joined, reconnected []int
+	ps.OnJoined(func(p *Player) {
+		joined = append(joined, p.Index)
+	})
+	ps.OnReconnected(func(p *Player) {
+		reconnected = append(reconnected, p.Index)
+	})
+
+	keyboard := ps.Join(0)
+
+	c := newTestController("pad")
+	c.current["start"].IsPressed = true
+	controllers = append(controllers, c)
+
+	ps.Update()
+
+	if keyboard.HasController() {
+		t.Error("the keyboard player was given the new controller")
+	}
+
+	if p := ps.Player(1); !p.IsJoined || p.controller != c {
+		t.Error("the new controller didn't join the next empty slot")
+	}
+
+	if len(joined) != 2 || joined[0] != 0 || joined[1] != 1 {
+		t.Errorf("joined hooks were called for %v, want [0 1]", joined)
+	}
+
+	if len(reconnected) != 0 {
+		t.Errorf("reconnected hooks were called for %v, want none", reconnected)
+	}
+}
+
+func TestPlayerSlotsReconnectTakesPriority(t *testing.T) {
+	isolateInput(t)
+
+	ps := NewPlayerSlots(nil, "start")
+
+	var reconnected []int
+	ps.OnReconnected(func(p *Player) {
+		reconnected = append(reconnected, p.Index)
+	})
+
+	ps.Join(0)
+
+	first := newTestController("first")
+	first.current["start"].IsPressed = true
+	controllers = append(controllers, first)
+
+	ps.Update()
+
+	// Unplugging keeps the GUID, in the same way as RemoveController
+	ps.Player(1).controller = nil
+	controllers = nil
+
+	ps.Update()
+
+	second := newTestController("second")
+	second.current["start"].IsPressed = true
+	controllers = append(controllers, second)
+
+	ps.Update()
+
+	if p := ps.Player(1); p.controller != second {
+		t.Error("the player who lost their controller didn't get the new one")
+	}
+
+	if ps.Player(0).HasController() {
+		t.Error("the keyboard player was given the new controller")
+	}
+
+	if len(reconnected) != 1 || reconnected[0] != 1 {
+		t.Errorf("reconnected hooks were called for %v, want [1]", reconnected)
+	}
+}
@@ -0,0 +1,173 @@
+package input
+
+import "github.com/veandco/go-sdl2/sdl"
+
+type TextKey byte
+
+const (
+	TextKeyNone TextKey = iota
+	TextKeyLeft
+	TextKeyRight
+	TextKeyUp
+	TextKeyDown
+	TextKeyHome
+	TextKeyEnd
+	TextKeyBackspace
+	TextKeyDelete
+	TextKeyEnter
+	TextKeyTab
+	TextKeyEscape
+	TextKeySelectAll
+	TextKeyCopy
+	TextKeyCut
+	TextKeyPaste
+)
+
+// TextKeyEvent is an editing key press received while text input is active.
+// Held keys produce repeated events at the operating system's repeat rate.
+type TextKeyEvent struct {
+	Key      TextKey
+	IsRepeat bool
+	Shift    bool
+	Ctrl     bool
+}
+
+// Text holds the text input that was received during the last call to Update.
+//
+// Runes and Keys only contain what was received since the previous frame, so
+// anything that wants to keep the text around has to copy it out, whereas the
+// composition is kept until the IME tells us that it has changed.
+var Text struct {
+	Runes             []rune
+	Keys              []TextKeyEvent
+	Composition       []rune
+	CompositionCursor int
+	CompositionLength int
+	pendingRunes      []rune
+	pendingKeys       []TextKeyEvent
+}
+
+func StartTextInput() {
+	sdl.StartTextInput()
+}
+
+func StopTextInput() {
+	sdl.StopTextInput()
+
+	Text.Composition = Text.Composition[:0]
+	Text.CompositionCursor = 0
+	Text.CompositionLength = 0
+}
+
+func IsTextInputActive() bool {
+	return sdl.IsTextInputActive()
+}
+
+// SetTextInputRect tells the IME where the text being edited is on screen so
+// that any candidate window can be placed next to it.
+func SetTextInputRect(x, y, width, height int) {
+	sdl.SetTextInputRect(&sdl.Rect{
+		X: int32(x),
+		Y: int32(y),
+		W: int32(width),
+		H: int32(height),
+	})
+}
+
+func Clipboard() (string, error) {
+	if !sdl.HasClipboardText() {
+		return "", nil
+	}
+
+	return sdl.GetClipboardText()
+}
+
+func SetClipboard(text string) error {
+	return sdl.SetClipboardText(text)
+}
+
+func HandleTextInputEvent(event sdl.TextInputEvent) {
+	Text.pendingRunes = append(Text.pendingRunes, []rune(event.GetText())...)
+
+	// Committed text always replaces whatever was being composed
+	Text.Composition = Text.Composition[:0]
+	Text.CompositionCursor = 0
+	Text.CompositionLength = 0
+}
+
+func HandleTextEditingEvent(event sdl.TextEditingEvent) {
+	Text.Composition = append(Text.Composition[:0], []rune(event.GetText())...)
+	Text.CompositionCursor = int(event.Start)
+	Text.CompositionLength = int(event.Length)
+}
+
+func HandleKeyboardEvent(event sdl.KeyboardEvent) {
+	// Editing keys are only of interest while the user is typing, the rest of
+	// the time they're handled through the regular device bindings
+	if event.Type != sdl.KEYDOWN || !sdl.IsTextInputActive() {
+		return
+	}
+
+	ctrl := event.Keysym.Mod&(sdl.KMOD_CTRL|sdl.KMOD_GUI) != 0
+
+	var key TextKey
+	switch event.Keysym.Sym {
+	case sdl.K_LEFT:
+		key = TextKeyLeft
+	case sdl.K_RIGHT:
+		key = TextKeyRight
+	case sdl.K_UP:
+		key = TextKeyUp
+	case sdl.K_DOWN:
+		key = TextKeyDown
+	case sdl.K_HOME:
+		key = TextKeyHome
+	case sdl.K_END:
+		key = TextKeyEnd
+	case sdl.K_BACKSPACE:
+		key = TextKeyBackspace
+	case sdl.K_DELETE:
+		key = TextKeyDelete
+	case sdl.K_RETURN, sdl.K_KP_ENTER:
+		key = TextKeyEnter
+	case sdl.K_TAB:
+		key = TextKeyTab
+	case sdl.K_ESCAPE:
+		key = TextKeyEscape
+	case sdl.K_a:
+		if ctrl {
+			key = TextKeySelectAll
+		}
+	case sdl.K_c:
+		if ctrl {
+			key = TextKeyCopy
+		}
+	case sdl.K_x:
+		if ctrl {
+			key = TextKeyCut
+		}
+	case sdl.K_v:
+		if ctrl {
+			key = TextKeyPaste
+		}
+	}
+
+	if key == TextKeyNone {
+		return
+	}
+
+	Text.pendingKeys = append(Text.pendingKeys, TextKeyEvent{
+		Key:      key,
+		IsRepeat: event.Repeat != 0,
+		Shift:    event.Keysym.Mod&sdl.KMOD_SHIFT != 0,
+		Ctrl:     ctrl,
+	})
+}
+
+func updateText() {
+	// Events are handled before Update is called each frame, so we swap the
+	// pending buffers in to make them visible and reuse the old ones for the
+	// events that arrive before the next frame
+	Text.Runes, Text.pendingRunes = Text.pendingRunes, Text.Runes[:0]
+	Text.Keys, Text.pendingKeys = Text.pendingKeys, Text.Keys[:0]
+}
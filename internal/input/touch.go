@@ -0,0 +1,387 @@
+package input
+
+import (
+	"math"
+	"time"
+
+	"github.com/robotscone/adventure/internal/gfx"
+	"github.com/robotscone/adventure/internal/linalg"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+type GestureKind byte
+
+const (
+	GestureTap GestureKind = iota
+	GestureLongPress
+	GestureSwipe
+	GesturePinch
+	GesturePan
+)
+
+// Gesture is a gesture that was recognised during the last call to Update.
+//
+// Position is where the gesture happened, or the point between both fingers
+// for the two finger gestures.
+// Delta is the total movement of a swipe or the movement of a pan this frame.
+// Scale is how much the distance between the fingers of a pinch has changed
+// this frame, so it's less than 1 when pinching in.
+type Gesture struct {
+	Kind     GestureKind
+	Position linalg.Vec2
+	Delta    linalg.Vec2
+	Scale    float64
+}
+
+// Finger is a single point of contact on a touch device.
+// Positions are in the same logical coordinates as the mouse.
+type Finger struct {
+	ID         sdl.FingerID
+	Position   linalg.Vec2
+	Delta      linalg.Vec2
+	Start      linalg.Vec2
+	Pressure   float64
+	StartedAt  time.Time
+	IsPressed  bool
+	IsReleased bool
+
+	normalized    linalg.Vec2
+	isLifted      bool
+	isClaimed     bool
+	isMulti       bool
+	isLongPressed bool
+}
+
+// VirtualButton is an on-screen button that can be bound using
+// "touch:button:<name>".
+type VirtualButton struct {
+	Position linalg.Vec2
+	Radius   float64
+	IsDown   bool
+	finger   *Finger
+}
+
+// VirtualStick is an on-screen joystick that can be bound using
+// "touch:stick:<name>:<direction>", where direction is one of up, down, left
+// or right.
+// Value has a magnitude of at most 1, with positive Y pointing down.
+type VirtualStick struct {
+	Position linalg.Vec2
+	Radius   float64
+	Value    linalg.Vec2
+	IsDown   bool
+	finger   *Finger
+}
+
+// Touch holds the state of every finger on every touch device along with the
+// gestures that were recognised during the last call to Update.
+//
+// The durations and distances used to recognise gestures can be changed to
+// suit the game, with distances being in logical coordinates.
+var Touch = struct {
+	Fingers           []*Finger
+	Gestures          []Gesture
+	TapDuration       time.Duration
+	TapDistance       float64
+	LongPressDuration time.Duration
+	SwipeDuration     time.Duration
+	SwipeDistance     float64
+	buttons           map[string]*VirtualButton
+	sticks            map[string]*VirtualStick
+	values            map[string]float64
+}{
+	TapDuration:       250 * time.Millisecond,
+	TapDistance:       10,
+	LongPressDuration: 500 * time.Millisecond,
+	SwipeDuration:     500 * time.Millisecond,
+	SwipeDistance:     50,
+	buttons:           make(map[string]*VirtualButton),
+	sticks:            make(map[string]*VirtualStick),
+	values:            make(map[string]float64),
+}
+
+func HandleTouchEvent(event sdl.TouchFingerEvent) {
+	var finger *Finger
+	for _, f := range Touch.Fingers {
+		if f.ID == event.FingerID && !f.isLifted {
+			finger = f
+
+			break
+		}
+	}
+
+	switch event.Type {
+	case sdl.FINGERDOWN:
+		finger = &Finger{
+			ID:        event.FingerID,
+			StartedAt: time.Now(),
+			IsPressed: true,
+		}
+
+		Touch.Fingers = append(Touch.Fingers, finger)
+	case sdl.FINGERUP:
+		if finger != nil {
+			finger.isLifted = true
+		}
+	}
+
+	if finger == nil {
+		return
+	}
+
+	finger.normalized = linalg.New(float64(event.X), float64(event.Y))
+	finger.Pressure = float64(event.Pressure)
+}
+
+func AddVirtualButton(name string, x, y, radius float64) *VirtualButton {
+	button := &VirtualButton{
+		Position: linalg.New(x, y),
+		Radius:   radius,
+	}
+
+	Touch.buttons[name] = button
+
+	return button
+}
+
+func RemoveVirtualButton(name string) {
+	delete(Touch.buttons, name)
+}
+
+func AddVirtualStick(name string, x, y, radius float64) *VirtualStick {
+	stick := &VirtualStick{
+		Position: linalg.New(x, y),
+		Radius:   radius,
+	}
+
+	Touch.sticks[name] = stick
+
+	return stick
+}
+
+func RemoveVirtualStick(name string) {
+	delete(Touch.sticks, name)
+}
+
+func touchToLogical(renderer *gfx.Renderer, window *sdl.Window, normalized linalg.Vec2) linalg.Vec2 {
+	if window == nil {
+		return normalized
+	}
+
+	width, height := window.GetSize()
+	windowX := int(normalized.X * float64(width))
+	windowY := int(normalized.Y * float64(height))
+
+	if renderer == nil {
+		return linalg.New(float64(windowX), float64(windowY))
+	}
+
+	logicalX, logicalY := renderer.RenderWindowToLogical(windowX, windowY)
+
+	return linalg.New(float64(logicalX), float64(logicalY))
+}
+
+func updateTouch(renderer *gfx.Renderer, now time.Time) {
+	// Fingers that were lifted are kept around for a single frame so that
+	// their release can be seen, after which they're removed
+	fingers := Touch.Fingers[:0]
+	for _, finger := range Touch.Fingers {
+		if finger.IsReleased {
+			continue
+		}
+
+		fingers = append(fingers, finger)
+	}
+	for i := len(fingers); i < len(Touch.Fingers); i++ {
+		Touch.Fingers[i] = nil
+	}
+	Touch.Fingers = fingers
+
+	window := sdl.GetKeyboardFocus()
+	if renderer != nil {
+		if rendererWindow, err := renderer.GetWindow(); err == nil {
+			window = rendererWindow
+		}
+	}
+
+	for _, finger := range Touch.Fingers {
+		position := touchToLogical(renderer, window, finger.normalized)
+
+		// A finger that has only just touched down has nowhere to have moved
+		// from, so it starts off with no delta
+		if finger.IsPressed {
+			finger.Start = position
+			finger.Position = position
+		}
+
+		finger.Delta = position.Sub(finger.Position)
+		finger.Position = position
+		finger.IsReleased = finger.isLifted
+	}
+
+	for key := range Touch.values {
+		delete(Touch.values, key)
+	}
+
+	Touch.Gestures = Touch.Gestures[:0]
+
+	updateVirtualWidgets()
+	recogniseGestures(now)
+
+	for _, finger := range Touch.Fingers {
+		finger.IsPressed = false
+	}
+}
+
+func updateVirtualWidgets() {
+	for _, finger := range Touch.Fingers {
+		if !finger.IsPressed || finger.isClaimed {
+			continue
+		}
+
+		for _, button := range Touch.buttons {
+			if button.finger == nil && finger.Position.Sub(button.Position).Mag() <= button.Radius {
+				button.finger = finger
+				finger.isClaimed = true
+
+				break
+			}
+		}
+
+		if finger.isClaimed {
+			continue
+		}
+
+		for _, stick := range Touch.sticks {
+			if stick.finger == nil && finger.Position.Sub(stick.Position).Mag() <= stick.Radius {
+				stick.finger = finger
+				finger.isClaimed = true
+
+				break
+			}
+		}
+	}
+
+	for name, button := range Touch.buttons {
+		// A button stays down until the finger holding it is lifted, even if
+		// the finger slides off of it
+		// A tap that was over before we got to see it still counts for a
+		// single frame so that it isn't lost
+		button.IsDown = button.finger != nil && (!button.finger.IsReleased || button.finger.IsPressed)
+		if !button.IsDown {
+			button.finger = nil
+
+			continue
+		}
+
+		Touch.values["button:"+name] = 1
+	}
+
+	for name, stick := range Touch.sticks {
+		stick.IsDown = stick.finger != nil && !stick.finger.IsReleased
+		if !stick.IsDown {
+			stick.finger = nil
+			stick.Value = linalg.Vec2{}
+
+			continue
+		}
+
+		stick.Value = stick.finger.Position.Sub(stick.Position).Mul(1 / stick.Radius)
+		if stick.Value.Mag() > 1 {
+			stick.Value = stick.Value.Norm()
+		}
+
+		Touch.values["stick:"+name+":left"] = math.Max(0, -stick.Value.X)
+		Touch.values["stick:"+name+":right"] = math.Max(0, stick.Value.X)
+		Touch.values["stick:"+name+":up"] = math.Max(0, -stick.Value.Y)
+		Touch.values["stick:"+name+":down"] = math.Max(0, stick.Value.Y)
+	}
+}
+
+func recogniseGestures(now time.Time) {
+	var active []*Finger
+	for _, finger := range Touch.Fingers {
+		if !finger.isClaimed && !finger.IsReleased {
+			active = append(active, finger)
+		}
+	}
+
+	if len(active) > 1 {
+		for _, finger := range active {
+			finger.isMulti = true
+		}
+	}
+
+	if len(active) == 2 && !active[0].IsPressed && !active[1].IsPressed {
+		a, b := active[0], active[1]
+		centre := a.Position.Add(b.Position).Mul(0.5)
+
+		previousDistance := a.Position.Sub(a.Delta).Sub(b.Position.Sub(b.Delta)).Mag()
+		distance := a.Position.Sub(b.Position).Mag()
+
+		if previousDistance > 0 && distance != previousDistance {
+			scale := distance / previousDistance
+
+			addGesture(Gesture{Kind: GesturePinch, Position: centre, Scale: scale})
+
+			if scale < 1 {
+				Touch.values["pinch:in"] = 1 - scale
+			} else {
+				Touch.values["pinch:out"] = scale - 1
+			}
+		}
+
+		if pan := a.Delta.Add(b.Delta).Mul(0.5); pan != (linalg.Vec2{}) {
+			addGesture(Gesture{Kind: GesturePan, Position: centre, Delta: pan})
+		}
+	}
+
+	for _, finger := range Touch.Fingers {
+		if finger.isClaimed || finger.isMulti || finger.isLongPressed {
+			continue
+		}
+
+		held := now.Sub(finger.StartedAt)
+		moved := finger.Position.Sub(finger.Start)
+
+		if !finger.IsReleased {
+			if held >= Touch.LongPressDuration && moved.Mag() <= Touch.TapDistance {
+				finger.isLongPressed = true
+
+				addGesture(Gesture{Kind: GestureLongPress, Position: finger.Position})
+
+				Touch.values["longpress"] = 1
+			}
+
+			continue
+		}
+
+		switch {
+		case held <= Touch.TapDuration && moved.Mag() <= Touch.TapDistance:
+			addGesture(Gesture{Kind: GestureTap, Position: finger.Position})
+
+			Touch.values["tap"] = 1
+		case held <= Touch.SwipeDuration && moved.Mag() >= Touch.SwipeDistance:
+			addGesture(Gesture{Kind: GestureSwipe, Position: finger.Start, Delta: moved})
+
+			// Swipes are bound by whichever axis they mostly moved along
+			if math.Abs(moved.X) > math.Abs(moved.Y) {
+				if moved.X < 0 {
+					Touch.values["swipe:left"] = 1
+				} else {
+					Touch.values["swipe:right"] = 1
+				}
+			} else {
+				if moved.Y < 0 {
+					Touch.values["swipe:up"] = 1
+				} else {
+					Touch.values["swipe:down"] = 1
+				}
+			}
+		}
+	}
+}
+
+func addGesture(gesture Gesture) {
+	Touch.Gestures = append(Touch.Gestures, gesture)
+}
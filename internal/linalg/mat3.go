@@ -0,0 +1,118 @@
+package linalg
+
+import "math"
+
+// Mat3 is a 3x3 matrix in row major order that's used for 2D affine
+// transforms, so the bottom row is always 0, 0, 1.
+//
+// Transforms are composed with Mul, where a.Mul(b) applies b first and then
+// a, so the world transform of a node in a scene graph is its parent's world
+// transform multiplied by its own local transform.
+type Mat3 [9]float64
+
+func Identity() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+func Translation(x, y float64) Mat3 {
+	return Mat3{
+		1, 0, x,
+		0, 1, y,
+		0, 0, 1,
+	}
+}
+
+func Rotation(radians float64) Mat3 {
+	sin, cos := math.Sincos(radians)
+
+	return Mat3{
+		cos, -sin, 0,
+		sin, cos, 0,
+		0, 0, 1,
+	}
+}
+
+func Scaling(x, y float64) Mat3 {
+	return Mat3{
+		x, 0, 0,
+		0, y, 0,
+		0, 0, 1,
+	}
+}
+
+func (m Mat3) Mul(rhs Mat3) Mat3 {
+	var out Mat3
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			out[row*3+col] = m[row*3]*rhs[col] + m[row*3+1]*rhs[3+col] + m[row*3+2]*rhs[6+col]
+		}
+	}
+
+	return out
+}
+
+// Translate, Rotate and Scale apply a transform before m, so they're in the
+// local space of m.
+func (m Mat3) Translate(x, y float64) Mat3 {
+	return m.Mul(Translation(x, y))
+}
+
+func (m Mat3) Rotate(radians float64) Mat3 {
+	return m.Mul(Rotation(radians))
+}
+
+func (m Mat3) Scale(x, y float64) Mat3 {
+	return m.Mul(Scaling(x, y))
+}
+
+// Transform transforms a point, which includes the translation.
+func (m Mat3) Transform(v Vec2) Vec2 {
+	return Vec2{
+		X: m[0]*v.X + m[1]*v.Y + m[2],
+		Y: m[3]*v.X + m[4]*v.Y + m[5],
+	}
+}
+
+// TransformVector transforms a direction, which ignores the translation.
+func (m Mat3) TransformVector(v Vec2) Vec2 {
+	return Vec2{
+		X: m[0]*v.X + m[1]*v.Y,
+		Y: m[3]*v.X + m[4]*v.Y,
+	}
+}
+
+func (m Mat3) Det() float64 {
+	return m[0]*(m[4]*m[8]-m[5]*m[7]) -
+		m[1]*(m[3]*m[8]-m[5]*m[6]) +
+		m[2]*(m[3]*m[7]-m[4]*m[6])
+}
+
+// Invert returns the inverse of the matrix and whether there was one, since
+// something that's been scaled to nothing can't be undone.
+func (m Mat3) Invert() (Mat3, bool) {
+	det := m.Det()
+	if det == 0 {
+		return Mat3{}, false
+	}
+
+	// The inverse is the adjugate, which is the transpose of the matrix of
+	// cofactors, divided by the determinant
+	r := 1 / det
+
+	return Mat3{
+		(m[4]*m[8] - m[5]*m[7]) * r,
+		(m[2]*m[7] - m[1]*m[8]) * r,
+		(m[1]*m[5] - m[2]*m[4]) * r,
+		(m[5]*m[6] - m[3]*m[8]) * r,
+		(m[0]*m[8] - m[2]*m[6]) * r,
+		(m[2]*m[3] - m[0]*m[5]) * r,
+		(m[3]*m[7] - m[4]*m[6]) * r,
+		(m[1]*m[6] - m[0]*m[7]) * r,
+		(m[0]*m[4] - m[1]*m[3]) * r,
+	}, true
+}
@@ -0,0 +1,65 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+// isReasonable filters out fuzzed values that would overflow or lose all of
+// their precision, which aren't what the properties are about.
+func isReasonable(values ...float64) bool {
+	for _, value := range values {
+		if math.IsNaN(value) || math.Abs(value) > 1e6 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isClose(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func FuzzMat3InvertRoundTrip(f *testing.F) {
+	f.Add(0.0, 0.0, 0.0, 1.0, 1.0)
+	f.Add(10.0, -20.0, math.Pi/3, 2.0, 0.5)
+	f.Add(-3.5, 100.0, -1.0, -1.0, 4.0)
+
+	f.Fuzz(func(t *testing.T, x, y, radians, scaleX, scaleY float64) {
+		if !isReasonable(x, y, radians, scaleX, scaleY) {
+			t.Skip()
+		}
+
+		// Scales close to zero can't be undone with any useful precision
+		if math.Abs(scaleX) < 1e-3 || math.Abs(scaleY) < 1e-3 || math.Abs(scaleX) > 1e3 || math.Abs(scaleY) > 1e3 {
+			t.Skip()
+		}
+
+		m := Translation(x, y).Rotate(radians).Scale(scaleX, scaleY)
+
+		inv, ok := m.Invert()
+		if !ok {
+			t.Fatalf("%v has no inverse", m)
+		}
+
+		identity := Identity()
+		for i, value := range m.Mul(inv) {
+			if !isClose(value, identity[i], 1e-6) {
+				t.Fatalf("m.Mul(inv) = %v, want the identity", m.Mul(inv))
+			}
+		}
+
+		p := Vec2{X: 1.5, Y: -2.5}
+		got := inv.Transform(m.Transform(p))
+		if !isClose(got.X, p.X, 1e-6) || !isClose(got.Y, p.Y, 1e-6) {
+			t.Fatalf("transforming %v there and back gave %v", p, got)
+		}
+	})
+}
+
+func TestMat3InvertSingular(t *testing.T) {
+	if _, ok := Scaling(0, 1).Invert(); ok {
+		t.Error("a matrix scaled to nothing should have no inverse")
+	}
+}
@@ -0,0 +1,88 @@
+package linalg
+
+// Scalar is any number type a Rect can be made of.
+type Scalar interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Rect is an axis aligned rectangle, which is used with int for things like
+// the source rectangles of textures and UI clipping, and with float64 for
+// positions in the world.
+// Rectangles include their left and top edges but not their right and bottom
+// edges, so rectangles that only share an edge don't intersect.
+type Rect[T Scalar] struct {
+	X      T
+	Y      T
+	Width  T
+	Height T
+}
+
+func (r Rect[T]) Right() T {
+	return r.X + r.Width
+}
+
+func (r Rect[T]) Bottom() T {
+	return r.Y + r.Height
+}
+
+func (r Rect[T]) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+func (r Rect[T]) Translate(x, y T) Rect[T] {
+	r.X += x
+	r.Y += y
+
+	return r
+}
+
+func (r Rect[T]) Contains(x, y T) bool {
+	return x >= r.X && x < r.Right() && y >= r.Y && y < r.Bottom()
+}
+
+func (r Rect[T]) ContainsRect(rhs Rect[T]) bool {
+	return rhs.X >= r.X && rhs.Right() <= r.Right() && rhs.Y >= r.Y && rhs.Bottom() <= r.Bottom()
+}
+
+func (r Rect[T]) Intersects(rhs Rect[T]) bool {
+	return !r.Intersect(rhs).IsEmpty()
+}
+
+// Intersect is the part of the rectangle that's inside rhs, which is how
+// rectangles are clipped.
+// Rectangles that don't intersect give an empty rectangle.
+func (r Rect[T]) Intersect(rhs Rect[T]) Rect[T] {
+	x := max(r.X, rhs.X)
+	y := max(r.Y, rhs.Y)
+	right := min(r.Right(), rhs.Right())
+	bottom := min(r.Bottom(), rhs.Bottom())
+
+	return Rect[T]{
+		X:      x,
+		Y:      y,
+		Width:  max(right-x, 0),
+		Height: max(bottom-y, 0),
+	}
+}
+
+// Union is the smallest rectangle that contains both rectangles, where empty
+// rectangles are ignored.
+func (r Rect[T]) Union(rhs Rect[T]) Rect[T] {
+	if r.IsEmpty() {
+		return rhs
+	}
+
+	if rhs.IsEmpty() {
+		return r
+	}
+
+	x := min(r.X, rhs.X)
+	y := min(r.Y, rhs.Y)
+
+	return Rect[T]{
+		X:      x,
+		Y:      y,
+		Width:  max(r.Right(), rhs.Right()) - x,
+		Height: max(r.Bottom(), rhs.Bottom()) - y,
+	}
+}
@@ -0,0 +1,70 @@
+package linalg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomRect(r *rand.Rand) Rect[int] {
+	// Widths and heights can be negative so that empty rectangles are covered
+	return Rect[int]{
+		X:      r.Intn(40) - 20,
+		Y:      r.Intn(40) - 20,
+		Width:  r.Intn(30) - 5,
+		Height: r.Intn(30) - 5,
+	}
+}
+
+func TestRectIntersect(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		a, b := randomRect(r), randomRect(r)
+		intersection := a.Intersect(b)
+
+		if other := b.Intersect(a); intersection != other {
+			t.Fatalf("%v.Intersect(%v) = %v, but the other way is %v", a, b, intersection, other)
+		}
+
+		if intersection.IsEmpty() {
+			continue
+		}
+
+		if !a.ContainsRect(intersection) || !b.ContainsRect(intersection) {
+			t.Fatalf("%v.Intersect(%v) = %v, which isn't inside both", a, b, intersection)
+		}
+	}
+}
+
+func TestRectUnion(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 10000; i++ {
+		a, b := randomRect(r), randomRect(r)
+		union := a.Union(b)
+
+		if !a.IsEmpty() && !union.ContainsRect(a) || !b.IsEmpty() && !union.ContainsRect(b) {
+			t.Fatalf("%v.Union(%v) = %v, which doesn't contain both", a, b, union)
+		}
+	}
+}
+
+func TestRectContainsAgreesWithIntersect(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 10000; i++ {
+		a, b := randomRect(r), randomRect(r)
+		intersection := a.Intersect(b)
+
+		x, y := r.Intn(60)-30, r.Intn(60)-30
+
+		inBoth := a.Contains(x, y) && b.Contains(x, y)
+		if inIntersection := intersection.Contains(x, y); inBoth != inIntersection {
+			t.Fatalf("(%v, %v) is in both %v and %v is %v, but in their intersection %v is %v", x, y, a, b, inBoth, intersection, inIntersection)
+		}
+
+		if a.Intersects(b) != !intersection.IsEmpty() {
+			t.Fatalf("%v.Intersects(%v) disagrees with their intersection %v", a, b, intersection)
+		}
+	}
+}
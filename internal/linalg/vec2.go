@@ -74,3 +74,65 @@ func (v Vec2) Reflect(normal Vec2) Vec2 {
 
 	return normalScaled.Sub(v)
 }
+
+func (v Vec2) Lerp(rhs Vec2, t float64) Vec2 {
+	return v.Add(rhs.Sub(v).Mul(t))
+}
+
+// Angle is the angle of the vector from the positive X axis in radians.
+func (v Vec2) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+func (v Vec2) Rotate(radians float64) Vec2 {
+	sin, cos := math.Sincos(radians)
+
+	return Vec2{
+		X: v.X*cos - v.Y*sin,
+		Y: v.X*sin + v.Y*cos,
+	}
+}
+
+// Perp is the vector rotated by 90 degrees in the same direction as a
+// positive rotation with Rotate.
+func (v Vec2) Perp() Vec2 {
+	return Vec2{
+		X: -v.Y,
+		Y: v.X,
+	}
+}
+
+// Cross is the Z component of the cross product of the vectors if they were
+// in 3D, which is positive when rhs is a positive rotation from v.
+func (v Vec2) Cross(rhs Vec2) float64 {
+	return v.X*rhs.Y - v.Y*rhs.X
+}
+
+func (v Vec2) DistanceSq(rhs Vec2) float64 {
+	return rhs.Sub(v).MagSq()
+}
+
+func (v Vec2) Distance(rhs Vec2) float64 {
+	return rhs.Sub(v).Mag()
+}
+
+// Project is the part of v that points in the direction of onto.
+func (v Vec2) Project(onto Vec2) Vec2 {
+	// Projecting onto a zero vector has no direction to go in
+	magSq := onto.MagSq()
+	if magSq == 0 {
+		return Vec2{}
+	}
+
+	return onto.Mul(v.Dot(onto) / magSq)
+}
+
+// ClampMag shortens the vector if its magnitude is more than max.
+func (v Vec2) ClampMag(max float64) Vec2 {
+	magSq := v.MagSq()
+	if magSq <= max*max {
+		return v
+	}
+
+	return v.Mul(max / math.Sqrt(magSq))
+}
@@ -0,0 +1,84 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func FuzzVec2Rotate(f *testing.F) {
+	f.Add(1.0, 0.0, math.Pi/2)
+	f.Add(-3.0, 4.0, 1.0)
+	f.Add(0.0, 0.0, -2.5)
+
+	f.Fuzz(func(t *testing.T, x, y, radians float64) {
+		if !isReasonable(x, y, radians) {
+			t.Skip()
+		}
+
+		v := Vec2{X: x, Y: y}
+		rotated := v.Rotate(radians)
+
+		if !isClose(rotated.Mag(), v.Mag(), 1e-9) {
+			t.Fatalf("%v rotated by %v has magnitude %v, want %v", v, radians, rotated.Mag(), v.Mag())
+		}
+	})
+}
+
+func FuzzVec2Project(f *testing.F) {
+	f.Add(3.0, 4.0, 1.0, 0.0)
+	f.Add(-2.0, 5.0, 3.0, -1.0)
+	f.Add(1.0, 1.0, 0.0, 0.0)
+
+	f.Fuzz(func(t *testing.T, x, y, ontoX, ontoY float64) {
+		if !isReasonable(x, y, ontoX, ontoY) {
+			t.Skip()
+		}
+
+		v := Vec2{X: x, Y: y}
+		onto := Vec2{X: ontoX, Y: ontoY}
+		p := v.Project(onto)
+
+		// A projection can't be longer than the vector it came from
+		if p.Mag() > v.Mag()*(1+1e-9)+1e-9 {
+			t.Fatalf("%v projected onto %v is %v, which is longer", v, onto, p)
+		}
+
+		if onto.MagSq() < 1e-6 {
+			return
+		}
+
+		// What's left over after the projection is at right angles to onto
+		rest := v.Sub(p)
+		if math.Abs(rest.Dot(onto.Norm())) > 1e-6*math.Max(1, v.Mag()) {
+			t.Fatalf("%v minus its projection onto %v isn't perpendicular to it", v, onto)
+		}
+	})
+}
+
+func FuzzVec2ClampMag(f *testing.F) {
+	f.Add(3.0, 4.0, 2.0)
+	f.Add(3.0, 4.0, 10.0)
+	f.Add(0.0, 0.0, 0.0)
+
+	f.Fuzz(func(t *testing.T, x, y, limit float64) {
+		if !isReasonable(x, y, limit) || limit < 0 {
+			t.Skip()
+		}
+
+		v := Vec2{X: x, Y: y}
+		clamped := v.ClampMag(limit)
+
+		if clamped.Mag() > limit*(1+1e-9) {
+			t.Fatalf("%v clamped to %v has magnitude %v", v, limit, clamped.Mag())
+		}
+
+		if v.Mag() <= limit && clamped != v {
+			t.Fatalf("%v is within %v but was changed to %v", v, limit, clamped)
+		}
+
+		// Clamping only shortens, so the direction stays the same
+		if clamped.Dot(v) < 0 {
+			t.Fatalf("%v clamped to %v changed direction to %v", v, limit, clamped)
+		}
+	})
+}
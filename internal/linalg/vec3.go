@@ -0,0 +1,87 @@
+package linalg
+
+import "math"
+
+type Vec3 struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+func NewVec3(x, y, z float64) Vec3 {
+	return Vec3{X: x, Y: y, Z: z}
+}
+
+func (v Vec3) Add(rhs Vec3) Vec3 {
+	return Vec3{
+		X: v.X + rhs.X,
+		Y: v.Y + rhs.Y,
+		Z: v.Z + rhs.Z,
+	}
+}
+
+func (v Vec3) Sub(rhs Vec3) Vec3 {
+	return Vec3{
+		X: v.X - rhs.X,
+		Y: v.Y - rhs.Y,
+		Z: v.Z - rhs.Z,
+	}
+}
+
+func (v Vec3) Mul(rhs float64) Vec3 {
+	return Vec3{
+		X: v.X * rhs,
+		Y: v.Y * rhs,
+		Z: v.Z * rhs,
+	}
+}
+
+func (v Vec3) Neg() Vec3 {
+	return Vec3{
+		X: -v.X,
+		Y: -v.Y,
+		Z: -v.Z,
+	}
+}
+
+func (v Vec3) Dot(rhs Vec3) float64 {
+	return v.X*rhs.X + v.Y*rhs.Y + v.Z*rhs.Z
+}
+
+func (v Vec3) Cross(rhs Vec3) Vec3 {
+	return Vec3{
+		X: v.Y*rhs.Z - v.Z*rhs.Y,
+		Y: v.Z*rhs.X - v.X*rhs.Z,
+		Z: v.X*rhs.Y - v.Y*rhs.X,
+	}
+}
+
+func (v Vec3) MagSq() float64 {
+	return v.Dot(v)
+}
+
+func (v Vec3) Mag() float64 {
+	return math.Sqrt(v.MagSq())
+}
+
+func (v Vec3) Norm() Vec3 {
+	// A zero vector cannot be normalised
+	if v.X == 0 && v.Y == 0 && v.Z == 0 {
+		return v
+	}
+
+	return v.Mul(1.0 / v.Mag())
+}
+
+func (v Vec3) Lerp(rhs Vec3, t float64) Vec3 {
+	return v.Add(rhs.Sub(v).Mul(t))
+}
+
+func (v Vec3) Distance(rhs Vec3) float64 {
+	return rhs.Sub(v).Mag()
+}
+
+// XY drops the Z component, such as when a depth has been used for sorting.
+func (v Vec3) XY() Vec2 {
+	return Vec2{X: v.X, Y: v.Y}
+}
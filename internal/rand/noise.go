@@ -0,0 +1,192 @@
+package rand
+
+import "math"
+
+// Noise generates coherent noise, where nearby inputs give similar outputs,
+// which is what terrain and camera shake need rather than plain random
+// numbers.
+// Every function returns a value in roughly [-1, 1], and the same seed
+// always gives the same noise.
+type Noise struct {
+	perm [512]uint8
+}
+
+func NewNoise(seed uint64) *Noise {
+	n := &Noise{}
+	r := New(seed)
+
+	var perm [256]uint8
+	for i := range perm {
+		perm[i] = uint8(i)
+	}
+
+	Shuffle(r, perm[:])
+
+	// The table is repeated so that adding to an index from it never has to
+	// wrap
+	for i := range n.perm {
+		n.perm[i] = perm[i&255]
+	}
+
+	return n
+}
+
+// fade is the quintic curve from improved Perlin noise, which smooths out
+// the seams between lattice cells.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func (n *Noise) hash(x, y int) uint8 {
+	return n.perm[int(n.perm[x&255])+(y&255)]
+}
+
+// Value2 is value noise, which interpolates random values at the corners of
+// each cell and looks blockier than gradient noise, but is cheaper.
+func (n *Noise) Value2(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	xi, yi := int(x0), int(y0)
+	u, v := fade(x-x0), fade(y-y0)
+
+	value := func(x, y int) float64 {
+		return float64(n.hash(x, y))/127.5 - 1
+	}
+
+	return lerp(
+		lerp(value(xi, yi), value(xi+1, yi), u),
+		lerp(value(xi, yi+1), value(xi+1, yi+1), u),
+		v,
+	)
+}
+
+func grad1(hash uint8, x float64) float64 {
+	// The gradients are spread between -8 and 8 and skip 0, which is then
+	// brought back down to around [-1, 1] by the caller
+	g := float64(hash&7) + 1
+	if hash&8 != 0 {
+		g = -g
+	}
+
+	return g * x
+}
+
+// Perlin1 is one dimensional gradient noise, which is good for anything
+// that wobbles over time such as camera shake.
+func (n *Noise) Perlin1(x float64) float64 {
+	x0 := math.Floor(x)
+	xi := int(x0)
+	x -= x0
+
+	a := grad1(n.perm[xi&255], x)
+	b := grad1(n.perm[(xi+1)&255], x-1)
+
+	return lerp(a, b, fade(x)) * 0.25
+}
+
+func grad2(hash uint8, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+// Perlin2 is two dimensional gradient noise.
+func (n *Noise) Perlin2(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	xi, yi := int(x0), int(y0)
+	x, y = x-x0, y-y0
+	u, v := fade(x), fade(y)
+
+	return lerp(
+		lerp(grad2(n.hash(xi, yi), x, y), grad2(n.hash(xi+1, yi), x-1, y), u),
+		lerp(grad2(n.hash(xi, yi+1), x, y-1), grad2(n.hash(xi+1, yi+1), x-1, y-1), u),
+		v,
+	)
+}
+
+// Simplex2 is two dimensional simplex noise, which has fewer directional
+// artifacts than Perlin2 and is a bit cheaper.
+func (n *Noise) Simplex2(x, y float64) float64 {
+	// The factors that skew the input onto a grid of triangles and back again
+	const f2 = 0.36602540378443864676 // (sqrt(3) - 1) / 2
+	const g2 = 0.21132486540518711775 // (3 - sqrt(3)) / 6
+
+	s := (x + y) * f2
+	i, j := math.Floor(x+s), math.Floor(y+s)
+
+	t := (i + j) * g2
+	x0, y0 := x-(i-t), y-(j-t)
+
+	// Which of the two triangles in the cell we're in decides the middle
+	// corner
+	var i1, j1 int
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+
+	x1, y1 := x0-float64(i1)+g2, y0-float64(j1)+g2
+	x2, y2 := x0-1+2*g2, y0-1+2*g2
+
+	ii, jj := int(i), int(j)
+
+	corner := func(hash uint8, x, y float64) float64 {
+		t := 0.5 - x*x - y*y
+		if t < 0 {
+			return 0
+		}
+
+		t *= t
+
+		return t * t * grad2(hash, x, y)
+	}
+
+	total := corner(n.hash(ii, jj), x0, y0) +
+		corner(n.hash(ii+i1, jj+j1), x1, y1) +
+		corner(n.hash(ii+1, jj+1), x2, y2)
+
+	return 70 * total
+}
+
+// Fractal2 adds together octaves of noise at increasing frequencies and
+// decreasing amplitudes, which gives the detail of natural terrain.
+// Lacunarity is how much the frequency goes up by for each octave and gain
+// is how much the amplitude goes down by, where 2 and 0.5 are typical.
+func Fractal2(noise func(x, y float64) float64, x, y float64, octaves int, lacunarity, gain float64) float64 {
+	var total, max float64
+
+	frequency, amplitude := 1.0, 1.0
+
+	for i := 0; i < octaves; i++ {
+		total += noise(x*frequency, y*frequency) * amplitude
+		max += amplitude
+
+		frequency *= lacunarity
+		amplitude *= gain
+	}
+
+	if max == 0 {
+		return 0
+	}
+
+	return total / max
+}
@@ -0,0 +1,61 @@
+package rand
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoiseRange(t *testing.T) {
+	n := NewNoise(1)
+	r := New(2)
+
+	noises := map[string]func(x, y float64) float64{
+		"Value2":   n.Value2,
+		"Perlin1":  func(x, _ float64) float64 { return n.Perlin1(x) },
+		"Perlin2":  n.Perlin2,
+		"Simplex2": n.Simplex2,
+		"Fractal2": func(x, y float64) float64 {
+			return Fractal2(n.Perlin2, x, y, 4, 2, 0.5)
+		},
+	}
+
+	for name, noise := range noises {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10000; i++ {
+				x, y := r.FloatRange(-300, 300), r.FloatRange(-300, 300)
+
+				// The noise is only roughly in [-1, 1], so leave a little room
+				if v := noise(x, y); math.IsNaN(v) || v < -1.1 || v > 1.1 {
+					t.Fatalf("%s(%v, %v) = %v", name, x, y, v)
+				}
+			}
+		})
+	}
+}
+
+func TestNoiseIsDeterministic(t *testing.T) {
+	a, b := NewNoise(9), NewNoise(9)
+
+	for _, p := range [][2]float64{{0.5, 0.5}, {-12.3, 4.56}, {100.1, -7.9}} {
+		if a.Perlin2(p[0], p[1]) != b.Perlin2(p[0], p[1]) {
+			t.Errorf("Perlin2(%v) differs between noises with the same seed", p)
+		}
+	}
+}
+
+func TestNoiseIsZeroOnLattice(t *testing.T) {
+	n := NewNoise(4)
+
+	// Gradient noise is always 0 at whole numbers
+	for i := -5; i <= 5; i++ {
+		x := float64(i)
+
+		if v := n.Perlin1(x); v != 0 {
+			t.Errorf("Perlin1(%v) = %v, want 0", x, v)
+		}
+
+		if v := n.Perlin2(x, x); v != 0 {
+			t.Errorf("Perlin2(%v, %v) = %v, want 0", x, x, v)
+		}
+	}
+}
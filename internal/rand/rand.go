@@ -0,0 +1,236 @@
+// Package rand provides deterministic random numbers for anything that has
+// to play out the same way again, such as replays, tests and procedurally
+// generated maps.
+//
+// Unlike math/rand, the full state of a generator can be saved and restored,
+// and the sequence for a seed will never change between versions.
+package rand
+
+import (
+	"math"
+	"math/bits"
+)
+
+// State is everything a Rand needs to carry on from where it was, which can
+// be saved with encoding/json or encoding/gob.
+type State [4]uint64
+
+// Rand is a xoshiro256** generator, which is fast, small and good enough for
+// anything that isn't cryptography.
+// It isn't safe to use from more than one goroutine at a time.
+//
+// Generators have to be created with New, as the zero value has a state of
+// all zeros, which xoshiro can never leave and so only ever gives 0.
+type Rand struct {
+	s State
+}
+
+func splitMix64(x *uint64) uint64 {
+	*x += 0x9e3779b97f4a7c15
+
+	z := *x
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return z ^ (z >> 31)
+}
+
+func New(seed uint64) *Rand {
+	r := &Rand{}
+	r.Seed(seed)
+
+	return r
+}
+
+// Seed resets the generator so that it gives the same sequence as a new one
+// created with the seed.
+func (r *Rand) Seed(seed uint64) {
+	// The state can't be all zeros, which splitmix64 makes sure of no matter
+	// what the seed is, as well as spreading similar seeds far apart
+	for i := range r.s {
+		r.s[i] = splitMix64(&seed)
+	}
+}
+
+func (r *Rand) State() State {
+	return r.s
+}
+
+// SetState carries on from a state returned by State, and panics if the
+// state is all zeros, which can't have come from a generator.
+func (r *Rand) SetState(s State) {
+	if s == (State{}) {
+		panic("invalid all zero state for SetState")
+	}
+
+	r.s = s
+}
+
+func (r *Rand) Uint64() uint64 {
+	s := &r.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+
+	t := s[1] << 17
+
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+
+	return result
+}
+
+// Float64 returns a number in [0, 1).
+func (r *Rand) Float64() float64 {
+	// The top 53 bits fill the mantissa exactly, so every value is equally
+	// likely and 1 can never come up
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Intn returns a number in [0, n), and panics if n isn't positive.
+func (r *Rand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+
+	return int(r.uint64n(uint64(n)))
+}
+
+// uint64n returns a number in [0, bound), where bound isn't 0.
+func (r *Rand) uint64n(bound uint64) uint64 {
+	// Taking the high bits of a 128 bit product is Lemire's method, where we
+	// only have to throw away a result when it lands in the small part of the
+	// range that would make some numbers more likely than others
+	hi, lo := bits.Mul64(r.Uint64(), bound)
+
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.Uint64(), bound)
+		}
+	}
+
+	return hi
+}
+
+// IntRange returns a number in [min, max], including max.
+func (r *Rand) IntRange(min, max int) int {
+	if max < min {
+		min, max = max, min
+	}
+
+	// The size of the range is worked out without signs so that it can't
+	// overflow, and a range that covers every int has nothing to bound
+	span := uint64(max) - uint64(min)
+	if span == math.MaxUint64 {
+		return int(r.Uint64())
+	}
+
+	return min + int(r.uint64n(span+1))
+}
+
+// FloatRange returns a number in [min, max).
+func (r *Rand) FloatRange(min, max float64) float64 {
+	return min + r.Float64()*(max-min)
+}
+
+func (r *Rand) Bool() bool {
+	return r.Uint64()&1 == 1
+}
+
+// Chance returns true with the given probability, where 0.25 is true a
+// quarter of the time.
+func (r *Rand) Chance(probability float64) bool {
+	return r.Float64() < probability
+}
+
+// Sign returns either -1 or 1.
+func (r *Rand) Sign() float64 {
+	if r.Bool() {
+		return 1
+	}
+
+	return -1
+}
+
+// NormFloat64 returns a normally distributed number with a mean of 0 and a
+// standard deviation of 1, which is good for jitter that's usually small.
+func (r *Rand) NormFloat64() float64 {
+	// The Box-Muller transform, where 1 - Float64 keeps the log away from 0
+	u := 1 - r.Float64()
+	v := r.Float64()
+
+	return math.Sqrt(-2*math.Log(u)) * math.Cos(2*math.Pi*v)
+}
+
+// Weighted picks an index with a chance that's proportional to its weight,
+// and returns -1 if none of the weights are positive.
+// Negative weights are treated as 0.
+func (r *Rand) Weighted(weights []float64) int {
+	var total float64
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+
+	if total <= 0 {
+		return -1
+	}
+
+	target := r.Float64() * total
+	last := -1
+
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+
+		last = i
+
+		target -= w
+		if target < 0 {
+			return i
+		}
+	}
+
+	// Rounding can leave a tiny bit of the target, in which case it belongs
+	// to the last item that could have been picked
+	return last
+}
+
+// Pick returns a random item, and panics if there aren't any.
+func Pick[T any](r *Rand, items []T) T {
+	return items[r.Intn(len(items))]
+}
+
+// PickWeighted returns a random item with a chance that's proportional to its
+// weight, and returns false if none of the weights are positive.
+func PickWeighted[T any](r *Rand, items []T, weight func(item T) float64) (T, bool) {
+	weights := make([]float64, len(items))
+	for i, item := range items {
+		weights[i] = weight(item)
+	}
+
+	i := r.Weighted(weights)
+	if i < 0 {
+		var zero T
+
+		return zero, false
+	}
+
+	return items[i], true
+}
+
+// Shuffle puts the items in a random order in place.
+func Shuffle[T any](r *Rand, items []T) {
+	// Fisher-Yates, going backwards so each item swaps with one that hasn't
+	// been placed yet
+	for i := len(items) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		items[i], items[j] = items[j], items[i]
+	}
+}
@@ -0,0 +1,278 @@
+package rand
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// The sequences for a seed must never change, as saved games and replays
+// depend on them.
+func TestGoldenSequences(t *testing.T) {
+	r := New(1)
+
+	wantState := State{0x910a2dec89025cc1, 0xbeeb8da1658eec67, 0xf893a2eefb32555e, 0x71c18690ee42c90b}
+	if r.State() != wantState {
+		t.Errorf("New(1).State() = %#x, want %#x", r.State(), wantState)
+	}
+
+	wantUint64 := []uint64{0xb3f2af6d0fc710c5, 0x853b559647364cea, 0x92f89756082a4514, 0x642e1c7bc266a3a7}
+	for i, want := range wantUint64 {
+		if got := r.Uint64(); got != want {
+			t.Errorf("Uint64() #%d = %#x, want %#x", i, got, want)
+		}
+	}
+
+	r = New(42)
+
+	wantIntn := []int{8, 37, 68, 92, 99, 76, 71, 85}
+	for i, want := range wantIntn {
+		if got := r.Intn(100); got != want {
+			t.Errorf("Intn(100) #%d = %d, want %d", i, got, want)
+		}
+	}
+
+	r = New(7)
+
+	wantRange := []int{1, -2, 2, 3, 3, 3}
+	for i, want := range wantRange {
+		if got := r.IntRange(-3, 3); got != want {
+			t.Errorf("IntRange(-3, 3) #%d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestSplitMix64(t *testing.T) {
+	// The first output for a seed of 0 from the reference implementation
+	x := uint64(0)
+	if got := splitMix64(&x); got != 0xe220a8397b1dcdaf {
+		t.Errorf("splitMix64(0) = %#x, want 0xe220a8397b1dcdaf", got)
+	}
+}
+
+func TestSeedRestarts(t *testing.T) {
+	r := New(99)
+	first := r.Uint64()
+
+	r.Uint64()
+	r.Seed(99)
+
+	if got := r.Uint64(); got != first {
+		t.Errorf("Uint64() after Seed = %#x, want %#x", got, first)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	r := New(5)
+	for i := 0; i < 10; i++ {
+		r.Uint64()
+	}
+
+	saved := r.State()
+
+	want := make([]uint64, 16)
+	for i := range want {
+		want[i] = r.Uint64()
+	}
+
+	restored := New(0)
+	restored.SetState(saved)
+
+	for i := range want {
+		if got := restored.Uint64(); got != want[i] {
+			t.Fatalf("Uint64() #%d after SetState = %#x, want %#x", i, got, want[i])
+		}
+	}
+}
+
+func TestSetStateRejectsZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetState(State{}) did not panic")
+		}
+	}()
+
+	New(1).SetState(State{})
+}
+
+func TestIntnBounds(t *testing.T) {
+	r := New(3)
+
+	for _, n := range []int{1, 2, 3, 7, 100, math.MaxInt} {
+		for i := 0; i < 1000; i++ {
+			if got := r.Intn(n); got < 0 || got >= n {
+				t.Fatalf("Intn(%d) = %d", n, got)
+			}
+		}
+	}
+}
+
+func TestIntRangeBounds(t *testing.T) {
+	r := New(4)
+
+	tests := []struct {
+		min, max int
+	}{
+		{0, 0},
+		{-5, 5},
+		{5, -5},
+		{math.MinInt, math.MinInt + 1},
+		{math.MaxInt - 1, math.MaxInt},
+		{math.MinInt, 0},
+		{-1, math.MaxInt},
+		{math.MinInt, math.MaxInt},
+	}
+
+	for _, test := range tests {
+		lo, hi := min(test.min, test.max), max(test.min, test.max)
+
+		for i := 0; i < 1000; i++ {
+			if got := r.IntRange(test.min, test.max); got < lo || got > hi {
+				t.Fatalf("IntRange(%d, %d) = %d", test.min, test.max, got)
+			}
+		}
+	}
+}
+
+func TestIntRangeFullRangeUsesBothSigns(t *testing.T) {
+	r := New(8)
+
+	var isNegative, isPositive bool
+	for i := 0; i < 100; i++ {
+		n := r.IntRange(math.MinInt, math.MaxInt)
+		isNegative = isNegative || n < 0
+		isPositive = isPositive || n > 0
+	}
+
+	if !isNegative || !isPositive {
+		t.Error("IntRange(MinInt, MaxInt) only gave numbers of one sign")
+	}
+}
+
+func TestFloat64Bounds(t *testing.T) {
+	r := New(6)
+
+	for i := 0; i < 10000; i++ {
+		if got := r.Float64(); got < 0 || got >= 1 {
+			t.Fatalf("Float64() = %v", got)
+		}
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+		want    []int
+	}{
+		{"empty", nil, []int{-1}},
+		{"all zero", []float64{0, 0, 0}, []int{-1}},
+		{"all negative", []float64{-1, -2}, []int{-1}},
+		{"one positive", []float64{0, -3, 2, 0}, []int{2}},
+		{"negative ignored", []float64{1, -100, 1}, []int{0, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := New(11)
+
+			for i := 0; i < 1000; i++ {
+				if got := r.Weighted(test.weights); !slices.Contains(test.want, got) {
+					t.Fatalf("Weighted(%v) = %d, want one of %v", test.weights, got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedProportions(t *testing.T) {
+	r := New(12)
+	weights := []float64{1, 0, 3}
+
+	var counts [3]int
+	for i := 0; i < 40000; i++ {
+		counts[r.Weighted(weights)]++
+	}
+
+	if counts[1] != 0 {
+		t.Errorf("zero weight was picked %d times", counts[1])
+	}
+
+	// Index 2 should come up three times as often as index 0
+	if ratio := float64(counts[2]) / float64(counts[0]); ratio < 2.8 || ratio > 3.2 {
+		t.Errorf("ratio of picks = %v, want about 3", ratio)
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	r := New(13)
+
+	if _, ok := PickWeighted(r, []string{"a", "b"}, func(string) float64 { return 0 }); ok {
+		t.Error("PickWeighted with no positive weights reported an item")
+	}
+
+	got, ok := PickWeighted(r, []string{"a", "b"}, func(item string) float64 {
+		if item == "b" {
+			return 1
+		}
+
+		return -1
+	})
+	if !ok || got != "b" {
+		t.Errorf("PickWeighted = %q, %v, want b, true", got, ok)
+	}
+}
+
+func TestPickBounds(t *testing.T) {
+	r := New(14)
+	items := []int{10, 20, 30}
+
+	seen := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		item := Pick(r, items)
+		if !slices.Contains(items, item) {
+			t.Fatalf("Pick = %d, which isn't one of the items", item)
+		}
+
+		seen[item] = true
+	}
+
+	if len(seen) != len(items) {
+		t.Errorf("Pick only ever gave %v", seen)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Pick with no items did not panic")
+		}
+	}()
+
+	Pick(r, []int{})
+}
+
+func TestShuffle(t *testing.T) {
+	r := New(15)
+
+	Shuffle(r, []int{})
+	Shuffle(r, []int{1})
+
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	Shuffle(r, items)
+
+	sorted := slices.Clone(items)
+	slices.Sort(sorted)
+
+	for i, item := range sorted {
+		if item != i {
+			t.Fatalf("Shuffle lost or duplicated items: %v", items)
+		}
+	}
+
+	if slices.IsSorted(items) {
+		t.Error("Shuffle left 50 items in order")
+	}
+}
@@ -0,0 +1,62 @@
+package rand
+
+import (
+	"math"
+
+	"github.com/robotscone/adventure/internal/linalg"
+)
+
+// Angle returns an angle in [0, 2π) radians.
+func (r *Rand) Angle() float64 {
+	return r.Float64() * 2 * math.Pi
+}
+
+// UnitVector returns a vector with a magnitude of 1 pointing in a random
+// direction.
+func (r *Rand) UnitVector() linalg.Vec2 {
+	sin, cos := math.Sincos(r.Angle())
+
+	return linalg.New(cos, sin)
+}
+
+// InCircle returns a point inside the circle, where every point is equally
+// likely.
+func (r *Rand) InCircle(centre linalg.Vec2, radius float64) linalg.Vec2 {
+	return r.InRing(centre, 0, radius)
+}
+
+func (r *Rand) OnCircle(centre linalg.Vec2, radius float64) linalg.Vec2 {
+	return centre.Add(r.UnitVector().Mul(radius))
+}
+
+// InRing returns a point between the inner and outer radii, where every
+// point is equally likely.
+func (r *Rand) InRing(centre linalg.Vec2, inner, outer float64) linalg.Vec2 {
+	// Picking the distance uniformly would bunch points up towards the
+	// middle, since there's less area there, so we pick it by area instead
+	innerSq := inner * inner
+	distance := math.Sqrt(innerSq + r.Float64()*(outer*outer-innerSq))
+
+	return centre.Add(r.UnitVector().Mul(distance))
+}
+
+func (r *Rand) InRect(rect linalg.Rect[float64]) linalg.Vec2 {
+	return linalg.New(
+		rect.X+r.Float64()*rect.Width,
+		rect.Y+r.Float64()*rect.Height,
+	)
+}
+
+// InTriangle returns a point inside the triangle, where every point is
+// equally likely.
+func (r *Rand) InTriangle(a, b, c linalg.Vec2) linalg.Vec2 {
+	u, v := r.Float64(), r.Float64()
+
+	// A point in the parallelogram made from two of the edges is in the
+	// triangle half the time, and the other half it can be folded back in
+	if u+v > 1 {
+		u, v = 1-u, 1-v
+	}
+
+	return a.Add(b.Sub(a).Mul(u)).Add(c.Sub(a).Mul(v))
+}
@@ -0,0 +1,80 @@
+package rand
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// Streams hands out independent generators by name, all derived from a
+// single seed, so that one system using more or fewer random numbers than
+// before doesn't change what every other system gets.
+type Streams struct {
+	seed    uint64
+	streams map[string]*Rand
+}
+
+func NewStreams(seed uint64) *Streams {
+	return &Streams{
+		seed:    seed,
+		streams: make(map[string]*Rand),
+	}
+}
+
+func (s *Streams) seedFor(name string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	// Mixing the seed and the name through splitmix64 means streams with
+	// similar names still start from unrelated states
+	x := s.seed ^ h.Sum64()
+
+	return splitMix64(&x)
+}
+
+// Stream returns the generator for the name, creating it the first time
+// it's asked for.
+func (s *Streams) Stream(name string) *Rand {
+	r, ok := s.streams[name]
+	if !ok {
+		r = New(s.seedFor(name))
+		s.streams[name] = r
+	}
+
+	return r
+}
+
+// Names returns the names of the streams that have been created, in order.
+func (s *Streams) Names() []string {
+	names := make([]string, 0, len(s.streams))
+	for name := range s.streams {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// State returns the state of every stream that has been created.
+func (s *Streams) State() map[string]State {
+	states := make(map[string]State, len(s.streams))
+	for name, r := range s.streams {
+		states[name] = r.State()
+	}
+
+	return states
+}
+
+// SetState restores the streams from a saved state, where any stream that
+// isn't in it goes back to the start of its sequence.
+func (s *Streams) SetState(states map[string]State) {
+	for name, r := range s.streams {
+		if _, ok := states[name]; !ok {
+			r.Seed(s.seedFor(name))
+		}
+	}
+
+	for name, state := range states {
+		s.Stream(name).SetState(state)
+	}
+}
@@ -0,0 +1,80 @@
+package rand
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStreamsAreIndependent(t *testing.T) {
+	a := NewStreams(1)
+	b := NewStreams(1)
+
+	// Using one stream more in one set mustn't change what the other gets
+	for i := 0; i < 10; i++ {
+		a.Stream("loot").Uint64()
+	}
+
+	if a.Stream("ai").Uint64() != b.Stream("ai").Uint64() {
+		t.Error("streams with the same seed and name gave different numbers")
+	}
+
+	if a.Stream("ai").State() == a.Stream("loot").State() {
+		t.Error("streams with different names share a state")
+	}
+}
+
+func TestStreamsNames(t *testing.T) {
+	s := NewStreams(1)
+	s.Stream("b")
+	s.Stream("a")
+
+	if got := s.Names(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("Names() = %q, want [a b]", got)
+	}
+}
+
+func TestStreamsStateRoundTrip(t *testing.T) {
+	s := NewStreams(2)
+	s.Stream("ai").Uint64()
+	s.Stream("loot").Uint64()
+
+	saved := s.State()
+
+	wantAI := s.Stream("ai").Uint64()
+	wantLoot := s.Stream("loot").Uint64()
+
+	// A stream created after the save has to go back to its start, the same
+	// as if it had never been used
+	fresh := NewStreams(2).Stream("weather").Uint64()
+	s.Stream("weather").Uint64()
+	s.Stream("weather").Uint64()
+
+	s.SetState(saved)
+
+	if got := s.Stream("ai").Uint64(); got != wantAI {
+		t.Errorf("ai after SetState = %#x, want %#x", got, wantAI)
+	}
+
+	if got := s.Stream("loot").Uint64(); got != wantLoot {
+		t.Errorf("loot after SetState = %#x, want %#x", got, wantLoot)
+	}
+
+	if got := s.Stream("weather").Uint64(); got != fresh {
+		t.Errorf("weather after SetState = %#x, want %#x", got, fresh)
+	}
+}
+
+func TestStreamsSetStateIntoNewStreams(t *testing.T) {
+	s := NewStreams(3)
+	s.Stream("ai").Uint64()
+
+	saved := s.State()
+	want := s.Stream("ai").Uint64()
+
+	restored := NewStreams(3)
+	restored.SetState(saved)
+
+	if got := restored.Stream("ai").Uint64(); got != want {
+		t.Errorf("ai after SetState = %#x, want %#x", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package sequence
+
+// Step is a single part of a sequence, such as waiting for a second or
+// walking a character to a point.
+//
+// Start is called when the step is reached, and then Update is called every
+// frame until it's done.
+// When a step finishes part way through a frame it returns how much of the
+// delta it didn't use so that the next step can carry on from there, which
+// keeps sequences deterministic no matter how the frames are split up.
+type Step interface {
+	Start()
+	Update(delta float64) (left float64, isDone bool)
+}
+
+// Func is a step that's just a function, for anything that doesn't need to
+// be reset when it's started.
+type Func func(delta float64) (left float64, isDone bool)
+
+func (f Func) Start() {}
+
+func (f Func) Update(delta float64) (float64, bool) {
+	return f(delta)
+}
+
+// Sequence runs its steps one after the other, and is a Step itself so that
+// sequences can be nested.
+type Sequence struct {
+	steps         []Step
+	index         int
+	isStepStarted bool
+}
+
+func New(steps ...Step) *Sequence {
+	return &Sequence{steps: steps}
+}
+
+// Then adds more steps to the end of the sequence.
+func (s *Sequence) Then(steps ...Step) *Sequence {
+	s.steps = append(s.steps, steps...)
+
+	return s
+}
+
+// Start goes back to the first step, so a sequence can be run again once it
+// has finished.
+func (s *Sequence) Start() {
+	s.index = 0
+	s.isStepStarted = false
+}
+
+func (s *Sequence) IsFinished() bool {
+	return s.index >= len(s.steps)
+}
+
+func (s *Sequence) Update(delta float64) (float64, bool) {
+	// We keep going through the steps until one of them needs more time, so
+	// any steps that finish straight away all happen in the same frame
+	for s.index < len(s.steps) {
+		step := s.steps[s.index]
+
+		if !s.isStepStarted {
+			s.isStepStarted = true
+
+			step.Start()
+		}
+
+		left, isDone := step.Update(delta)
+		if !isDone {
+			return 0, false
+		}
+
+		delta = left
+
+		s.index++
+		s.isStepStarted = false
+	}
+
+	return delta, true
+}
@@ -0,0 +1,174 @@
+package sequence
+
+import (
+	"math"
+	"time"
+
+	"github.com/robotscone/adventure/internal/ease"
+	"github.com/robotscone/adventure/internal/input"
+)
+
+type wait struct {
+	elapsed  float64
+	duration float64
+}
+
+func (w *wait) Start() {
+	w.elapsed = 0
+}
+
+func (w *wait) Update(delta float64) (float64, bool) {
+	w.elapsed += delta
+
+	if w.elapsed < w.duration {
+		return 0, false
+	}
+
+	return w.elapsed - w.duration, true
+}
+
+func Wait(duration time.Duration) Step {
+	return &wait{duration: duration.Seconds()}
+}
+
+// WaitFor waits until the predicate returns true, which is checked once per
+// update.
+func WaitFor(predicate func() bool) Step {
+	return Func(func(delta float64) (float64, bool) {
+		if !predicate() {
+			return 0, false
+		}
+
+		return delta, true
+	})
+}
+
+// Playable is anything that WaitTween can play from the start, such as an
+// ease.Tween, ease.TweenOf or ease.Timeline.
+type Playable interface {
+	ease.Animator
+	Reset()
+	Duration() time.Duration
+}
+
+type waitTween struct {
+	tween   Playable
+	elapsed float64
+}
+
+func (w *waitTween) Start() {
+	w.tween.Reset()
+	w.elapsed = 0
+}
+
+func (w *waitTween) Update(delta float64) (float64, bool) {
+	w.tween.Update(delta)
+	w.elapsed += delta
+
+	if !w.tween.IsFinished() {
+		return 0, false
+	}
+
+	// Whatever the tween didn't need to reach its end is passed on to the
+	// next step, the same as Wait does
+	left := w.elapsed - w.tween.Duration().Seconds()
+
+	return math.Max(0, math.Min(left, delta)), true
+}
+
+// WaitTween plays the tween from the start and waits for it to finish.
+// The tween is updated by the step, so it shouldn't be updated or paused
+// anywhere else while the step is running.
+func WaitTween(tween Playable) Step {
+	return &waitTween{tween: tween}
+}
+
+// WaitAction waits until the action is pressed on the device.
+func WaitAction(device *input.Device, action string) Step {
+	return WaitFor(func() bool {
+		return device.Get(action).IsPressed
+	})
+}
+
+// Do calls the function and moves straight on to the next step.
+func Do(f func()) Step {
+	return Func(func(delta float64) (float64, bool) {
+		f()
+
+		return delta, true
+	})
+}
+
+type group struct {
+	steps  []Step
+	isDone []bool
+	isRace bool
+}
+
+func (g *group) Start() {
+	for i, step := range g.steps {
+		g.isDone[i] = false
+
+		step.Start()
+	}
+}
+
+func (g *group) Update(delta float64) (float64, bool) {
+	if len(g.steps) == 0 {
+		return delta, true
+	}
+
+	minLeft := math.Inf(1)
+	maxLeft := math.Inf(-1)
+	isAllDone := true
+	isAnyDone := false
+
+	for i, step := range g.steps {
+		if g.isDone[i] {
+			continue
+		}
+
+		left, isDone := step.Update(delta)
+		if !isDone {
+			isAllDone = false
+
+			continue
+		}
+
+		g.isDone[i] = true
+		isAnyDone = true
+
+		minLeft = math.Min(minLeft, left)
+		maxLeft = math.Max(maxLeft, left)
+	}
+
+	// A race is over as soon as the first step finishes, which is the one
+	// that had the most time left over
+	if g.isRace && isAnyDone {
+		return maxLeft, true
+	}
+
+	if !g.isRace && isAllDone {
+		// Steps that finished in an earlier frame have no time left over this
+		// frame, so the group finished when the slowest of this frame's did
+		if math.IsInf(minLeft, 1) {
+			minLeft = delta
+		}
+
+		return minLeft, true
+	}
+
+	return 0, false
+}
+
+// Parallel runs all of the steps at the same time and is done once every one
+// of them is.
+func Parallel(steps ...Step) Step {
+	return &group{steps: steps, isDone: make([]bool, len(steps))}
+}
+
+// Race runs all of the steps at the same time and is done as soon as any one
+// of them is, at which point the others are abandoned.
+func Race(steps ...Step) Step {
+	return &group{steps: steps, isDone: make([]bool, len(steps)), isRace: true}
+}
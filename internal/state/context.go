@@ -0,0 +1,126 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Context is a container of services that's passed to states, where services
+// are registered and resolved by their type, such as *gfx.Renderer,
+// *event.Broker, *timer.Timer or *input.Device.
+//
+// Contexts form a tree, where resolving a service that isn't registered in a
+// context looks for it in its parents.
+// The FSM gives each state a child of its root context when it's entered and
+// closes it when the state exits, so anything a state hands to the context
+// with RegisterOwned or OnClose is torn down along with it.
+type Context struct {
+	parent     *Context
+	services   map[reflect.Type]any
+	closeFuncs []func() error
+	delta      float64
+}
+
+func NewContext() *Context {
+	return &Context{
+		services: make(map[reflect.Type]any),
+	}
+}
+
+// Child creates a context whose services are looked up in this one when
+// they aren't registered in the child.
+func (c *Context) Child() *Context {
+	child := NewContext()
+	child.parent = c
+
+	return child
+}
+
+func (c *Context) root() *Context {
+	root := c
+	for root.parent != nil {
+		root = root.parent
+	}
+
+	return root
+}
+
+// Delta is the time in seconds passed to the latest call to FSM.Update.
+func (c *Context) Delta() float64 {
+	return c.root().delta
+}
+
+// OnClose registers a hook that's called when the context is closed.
+// Hooks are called in the opposite order they were registered in.
+func (c *Context) OnClose(hook func() error) {
+	c.closeFuncs = append(c.closeFuncs, hook)
+}
+
+// Close calls the close hooks of the context and removes all of its
+// services, returning any errors from the hooks joined together.
+func (c *Context) Close() error {
+	var errs []error
+
+	for i := len(c.closeFuncs) - 1; i >= 0; i-- {
+		if err := c.closeFuncs[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	c.closeFuncs = nil
+
+	for key := range c.services {
+		delete(c.services, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+func serviceKey[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Register adds a service to the context without taking ownership of it, so
+// a service that's shared between states, such as the renderer, isn't closed
+// along with the context of whichever state happened to register it.
+func Register[T any](c *Context, service T) {
+	key := serviceKey[T]()
+
+	if _, ok := c.services[key]; ok {
+		panic(fmt.Sprintf("duplicate service registration for %q", key))
+	}
+
+	c.services[key] = service
+}
+
+// RegisterOwned is like Register, but the context takes ownership of the
+// service and calls its Close method when the context is closed.
+func RegisterOwned[T interface{ Close() error }](c *Context, service T) {
+	Register(c, service)
+	c.OnClose(service.Close)
+}
+
+func Resolve[T any](c *Context) (T, bool) {
+	key := serviceKey[T]()
+
+	for ; c != nil; c = c.parent {
+		if service, ok := c.services[key]; ok {
+			return service.(T), true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// MustResolve is like Resolve, but panics if the service isn't registered.
+func MustResolve[T any](c *Context) T {
+	service, ok := Resolve[T](c)
+	if !ok {
+		panic(fmt.Sprintf("no service registered for %q", serviceKey[T]()))
+	}
+
+	return service
+}
@@ -0,0 +1,42 @@
+package state
+
+import "testing"
+
+type testCloser struct {
+	closed int
+}
+
+func (c *testCloser) Close() error {
+	c.closed++
+
+	return nil
+}
+
+type ownedCloser struct {
+	testCloser
+}
+
+func TestContextCloseOnlyClosesOwnedServices(t *testing.T) {
+	shared := &testCloser{}
+	owned := &ownedCloser{}
+
+	ctx := NewContext().Child()
+	Register(ctx, shared)
+	RegisterOwned(ctx, owned)
+
+	if err := ctx.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	if shared.closed != 0 {
+		t.Errorf("shared service closed %d times, want 0", shared.closed)
+	}
+
+	if owned.closed != 1 {
+		t.Errorf("owned service closed %d times, want 1", owned.closed)
+	}
+
+	if _, ok := Resolve[*ownedCloser](ctx); ok {
+		t.Error("service still resolves after Close")
+	}
+}
@@ -1,20 +1,95 @@
 package state
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robotscone/adventure/internal/event"
+)
 
 var base = &Base{}
 
+var (
+	ErrUnknownState = errors.New("unknown state")
+	ErrPopBase      = errors.New("attempted to pop the base state")
+)
+
+type ErrorHook func(err error)
+
+type transitionKind byte
+
+const (
+	transitionSwitch transitionKind = iota
+	transitionPush
+	transitionPop
+	transitionRestore
+)
+
+type pendingTransition struct {
+	kind    transitionKind
+	name    string
+	message any
+}
+
+type entry struct {
+	name    string
+	state   State
+	ctx     *Context
+	message any
+
+	// The policy is kept from when the state was entered, so that the state
+	// beneath it is resumed when it's popped only if it was paused
+	policy Policy
+}
+
+// StateEntered, StateExited, StatePaused and StateResumed are published to
+// the FSM's broker, if it has one, as states change so that debugging tools
+// can follow along.
+type StateEntered struct {
+	Name    string
+	Message any
+}
+
+type StateExited struct {
+	Name string
+}
+
+type StatePaused struct {
+	Name string
+}
+
+type StateResumed struct {
+	Name string
+}
+
+// FSM is a stack of states, where by default only the state on top of the
+// stack gets input and updates, but every state on the stack is drawn.
+// States can change this by implementing Overlay.
+//
+// Switch, Push and Pop don't change the stack straight away, instead the
+// transitions are queued and applied in the order they were requested at
+// the start of Update and again once the top state has finished updating.
+// That way a state is never changed while it's in the middle of running.
 type FSM struct {
-	data   *Data
-	stack  []State
-	states map[string]State
+	ctx        *Context
+	stack      []entry
+	states     map[string]State
+	pending    []pendingTransition
+	depth      int
+	decoders   map[string]messageDecoder
+	broker     *event.Broker
+	errorFuncs []ErrorHook
 }
 
-func NewFSM(data *Data) *FSM {
+// NewFSM creates an FSM whose states get their services from ctx, which is
+// also the context states are given in Init.
+func NewFSM(ctx *Context) *FSM {
 	fsm := &FSM{
-		data:   data,
-		stack:  []State{base},
-		states: make(map[string]State),
+		ctx:      ctx,
+		stack:    []entry{{state: base, ctx: ctx}},
+		states:   make(map[string]State),
+		depth:    1,
+		decoders: make(map[string]messageDecoder),
 	}
 
 	return fsm
@@ -27,68 +102,239 @@ func (f *FSM) RegisterState(name string, state State) {
 
 	f.states[name] = state
 
-	state.Init(f, f.data)
+	state.Init(f, f.ctx)
 }
 
-func (f *FSM) Switch(name string, message any) {
-	state, ok := f.states[name]
-	if !ok {
-		fmt.Printf("attempted to switch to unknown state %q\n", name)
+// SetBroker sets the broker that the lifecycle events are published to.
+func (f *FSM) SetBroker(b *event.Broker) {
+	f.broker = b
+}
 
-		return
+// OnError registers a hook that's called with any error that happens while
+// applying a transition.
+func (f *FSM) OnError(hook ErrorHook) {
+	f.errorFuncs = append(f.errorFuncs, hook)
+}
+
+func (f *FSM) Switch(name string, message any) error {
+	if _, ok := f.states[name]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownState, name)
 	}
 
-	n := len(f.stack) - 1
+	f.pending = append(f.pending, pendingTransition{kind: transitionSwitch, name: name, message: message})
+
+	// Switching from the base state is a push
+	if f.depth == 1 {
+		f.depth++
+	}
 
-	f.stack[n].Exit()
+	return nil
+}
 
-	f.stack[n] = state
+func (f *FSM) Push(name string, message any) error {
+	if _, ok := f.states[name]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownState, name)
+	}
 
-	state.Enter(f, f.data, message)
+	f.pending = append(f.pending, pendingTransition{kind: transitionPush, name: name, message: message})
+	f.depth++
+
+	return nil
 }
 
-func (f *FSM) Push(name string, message any) {
-	state, ok := f.states[name]
-	if !ok {
-		fmt.Printf("attempted to push unknown state %q\n", name)
+// Pop queues the top state to be removed from the stack, and returns
+// ErrPopBase if only the base state would be left to pop once the
+// transitions queued before it have been applied.
+func (f *FSM) Pop() error {
+	if f.depth <= 1 {
+		return ErrPopBase
+	}
+
+	f.pending = append(f.pending, pendingTransition{kind: transitionPop})
+	f.depth--
+
+	return nil
+}
+
+// Apply applies any pending transitions straight away, which is mostly
+// useful for setting up the initial state before the game loop starts.
+func (f *FSM) Apply() {
+	// Entering or resuming a state can queue more transitions, and those are
+	// applied in the same go
+	for len(f.pending) > 0 {
+		t := f.pending[0]
+
+		f.pending[0] = pendingTransition{}
+		f.pending = f.pending[1:]
+
+		switch t.kind {
+		case transitionSwitch:
+			f.applySwitch(t.name, t.message)
+		case transitionPush:
+			f.applyPush(t.name, t.message)
+		case transitionPop:
+			f.applyPop()
+		case transitionRestore:
+			f.applyRestore(t.message.([]restoredState))
+		}
+	}
+
+	f.pending = f.pending[:0]
+}
+
+func (f *FSM) applySwitch(name string, message any) {
+	n := len(f.stack) - 1
+
+	// Switching while only the base state is on the stack would replace it,
+	// so it's treated as a push instead
+	if n == 0 {
+		f.applyPush(name, message)
 
 		return
 	}
 
-	f.stack[len(f.stack)-1].Pause()
+	previous := f.stack[n]
+	state := f.states[name]
+	policy := policyOf(state)
 
-	f.stack = append(f.stack, state)
+	f.exit(previous)
 
-	state.Enter(f, f.data, message)
+	// The state beneath only stays paused if the new state blocks updates
+	// too, otherwise it would be resumed more or fewer times than paused
+	below := f.stack[n-1]
+	switch {
+	case previous.policy.IsBlockingUpdates && !policy.IsBlockingUpdates:
+		f.resume(below)
+	case !previous.policy.IsBlockingUpdates && policy.IsBlockingUpdates:
+		f.pause(below)
+	}
+
+	f.stack[n] = entry{name: name, state: state, ctx: f.ctx.Child(), message: message, policy: policy}
+
+	f.enter(f.stack[n], message)
 }
 
-func (f *FSM) Pop() {
-	if len(f.stack) == 0 {
-		fmt.Println("attempted to pop an empty state stack")
+func (f *FSM) applyPush(name string, message any) {
+	state := f.states[name]
+	policy := policyOf(state)
 
-		return
+	// A state that lets the states below it carry on updating doesn't pause
+	// them, otherwise a notification would stop the music playing
+	if policy.IsBlockingUpdates {
+		f.pause(f.stack[len(f.stack)-1])
 	}
 
+	f.stack = append(f.stack, entry{name: name, state: state, ctx: f.ctx.Child(), message: message, policy: policy})
+
+	f.enter(f.stack[len(f.stack)-1], message)
+}
+
+func (f *FSM) applyPop() {
 	n := len(f.stack) - 1
+	if n == 0 {
+		f.error(ErrPopBase)
 
-	f.stack[n].Exit()
+		return
+	}
+
+	popped := f.stack[n]
 
-	f.stack[n] = nil
+	f.exit(popped)
+
+	f.stack[n] = entry{}
 	f.stack = f.stack[:n]
 
-	f.stack[len(f.stack)-1].Resume(f, f.data)
+	if popped.policy.IsBlockingUpdates {
+		f.resume(f.stack[n-1])
+	}
 }
 
+func (f *FSM) pause(e entry) {
+	e.state.Pause()
+	publish(f, StatePaused{Name: e.name})
+}
+
+func (f *FSM) resume(e entry) {
+	e.state.Resume(f, e.ctx)
+	publish(f, StateResumed{Name: e.name})
+}
+
+func (f *FSM) enter(e entry, message any) {
+	e.state.Enter(f, e.ctx, message)
+	publish(f, StateEntered{Name: e.name, Message: message})
+}
+
+func (f *FSM) exit(e entry) {
+	e.state.Exit()
+
+	// The context is closed after the state has exited so that it can still
+	// use its own services on the way out
+	if err := e.ctx.Close(); err != nil {
+		f.error(fmt.Errorf("closing context of state %q: %w", e.name, err))
+	}
+
+	publish(f, StateExited{Name: e.name})
+}
+
+func (f *FSM) error(err error) {
+	for _, hook := range f.errorFuncs {
+		hook(err)
+	}
+}
+
+func publish[T any](f *FSM, e T) {
+	if f.broker == nil {
+		return
+	}
+
+	event.Publish(f.broker, e)
+}
+
+// Input gives input to the states from the top of the stack down, stopping
+// at the first state that captures it.
 func (f *FSM) Input() {
-	f.stack[len(f.stack)-1].Input(f, f.data)
+	for i := len(f.stack) - 1; i >= 0; i-- {
+		e := f.stack[i]
+
+		e.state.Input(f, e.ctx)
+
+		if policyOf(e.state).IsCapturingInput {
+			break
+		}
+	}
 }
 
-func (f *FSM) Update() {
-	f.stack[len(f.stack)-1].Update(f, f.data)
+// Update updates the states from the first one that blocks updates, working
+// up towards the top of the stack so that overlays see the latest state of
+// whatever is beneath them.
+func (f *FSM) Update(delta float64) {
+	f.ctx.root().delta = delta
+
+	f.Apply()
+
+	first := len(f.stack) - 1
+	for first > 0 && !policyOf(f.stack[first].state).IsBlockingUpdates {
+		first--
+	}
+
+	// The stack can't change until the transitions are applied, so it's safe
+	// to range over even if one of the states requests a transition
+	for _, e := range f.stack[first:] {
+		e.state.Update(f, e.ctx)
+	}
+
+	f.Apply()
 }
 
+// Draw draws the states from the first one that isn't transparent, working
+// up towards the top of the stack.
 func (f *FSM) Draw() {
-	for i := 0; i < len(f.stack); i++ {
-		f.stack[i].Draw()
+	first := len(f.stack) - 1
+	for first > 0 && policyOf(f.stack[first].state).IsTransparent {
+		first--
+	}
+
+	for i := first; i < len(f.stack); i++ {
+		f.stack[i].state.Draw()
 	}
 }
@@ -0,0 +1,425 @@
+package state
+
+import "fmt"
+
+// Hooks are the functions a state in a Chart calls as it's entered, updated
+// and exited, all of which are optional.
+type Hooks[C any] struct {
+	Enter  func(ctx C)
+	Update func(ctx C, delta float64)
+	Exit   func(ctx C)
+}
+
+type transition[C any] struct {
+	event string
+	to    *node[C]
+	guard func(ctx C) bool
+}
+
+type node[C any] struct {
+	id          int
+	name        string
+	parent      *node[C]
+	children    []*node[C]
+	initial     *node[C]
+	isParallel  bool
+	hooks       Hooks[C]
+	transitions []*transition[C]
+}
+
+func (n *node[C]) isDescendantOf(ancestor *node[C]) bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Chart describes a hierarchical state machine that any number of Machines
+// can be created from, which makes it suitable for giving every entity of
+// the same kind its own state for AI.
+//
+// States are either compound, where only one of their children is active at
+// a time, or parallel, where all of their children are active at the same
+// time as independent regions.
+// A state that's active also has all of its ancestors active, which are
+// updated before it and whose transitions apply to it too, so behaviour
+// that's common to a group of states only has to be written once.
+type Chart[C any] struct {
+	root   *node[C]
+	nodes  []*node[C]
+	byName map[string]*node[C]
+}
+
+func NewChart[C any]() *Chart[C] {
+	root := &node[C]{}
+
+	return &Chart[C]{
+		root:   root,
+		nodes:  []*node[C]{root},
+		byName: make(map[string]*node[C]),
+	}
+}
+
+func (c *Chart[C]) lookup(name string) *node[C] {
+	if name == "" {
+		return c.root
+	}
+
+	n, ok := c.byName[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown state %q", name))
+	}
+
+	return n
+}
+
+func (c *Chart[C]) add(name, parent string, hooks Hooks[C], isParallel bool) {
+	if _, ok := c.byName[name]; ok || name == "" {
+		panic(fmt.Sprintf("duplicate state registration for %q", name))
+	}
+
+	p := c.lookup(parent)
+
+	n := &node[C]{
+		id:         len(c.nodes),
+		name:       name,
+		parent:     p,
+		isParallel: isParallel,
+		hooks:      hooks,
+	}
+
+	// The first child of a compound state is its initial state unless it's
+	// changed with Initial
+	if p.initial == nil {
+		p.initial = n
+	}
+
+	p.children = append(p.children, n)
+
+	c.nodes = append(c.nodes, n)
+	c.byName[name] = n
+}
+
+// State adds a compound state, where parent is the name of the state it
+// belongs to or an empty string for a top level state.
+// Parents have to be added before their children.
+func (c *Chart[C]) State(name, parent string, hooks Hooks[C]) {
+	c.add(name, parent, hooks, false)
+}
+
+// Parallel adds a state whose children are all active at the same time.
+func (c *Chart[C]) Parallel(name, parent string, hooks Hooks[C]) {
+	c.add(name, parent, hooks, true)
+}
+
+// Initial sets the child that's entered when a compound state is entered
+// without a more specific target.
+func (c *Chart[C]) Initial(parent, child string) {
+	p := c.lookup(parent)
+	n := c.lookup(child)
+
+	if n.parent != p {
+		panic(fmt.Sprintf("state %q is not a child of %q", child, parent))
+	}
+
+	p.initial = n
+}
+
+// Transition adds a transition that's taken automatically during Update when
+// the from state is active and the guard returns true.
+// A nil guard always passes.
+func (c *Chart[C]) Transition(from, to string, guard func(ctx C) bool) {
+	c.On("", from, to, guard)
+}
+
+// On adds a transition that's only taken when the event is sent to the
+// machine with Send.
+func (c *Chart[C]) On(event, from, to string, guard func(ctx C) bool) {
+	if from == "" {
+		panic("transitions must come from a named state")
+	}
+
+	if to == "" {
+		panic("transitions must go to a named state")
+	}
+
+	f := c.lookup(from)
+
+	f.transitions = append(f.transitions, &transition[C]{
+		event: event,
+		to:    c.lookup(to),
+		guard: guard,
+	})
+}
+
+// New creates a machine for the given context, which is passed to all of
+// the hooks and guards.
+// The machine doesn't enter any states until Start is called.
+func (c *Chart[C]) New(ctx C) *Machine[C] {
+	return &Machine[C]{
+		chart:  c,
+		ctx:    ctx,
+		active: make([]bool, len(c.nodes)),
+		exited: make([]bool, len(c.nodes)),
+	}
+}
+
+// Machine is a running instance of a Chart.
+//
+// Events sent from inside a hook or guard are queued until the machine has
+// finished what it's in the middle of, and are then taken in the order they
+// were sent, so a state is never entered or exited halfway through another
+// transition.
+type Machine[C any] struct {
+	chart      *Chart[C]
+	ctx        C
+	active     []bool
+	exited     []bool
+	leaves     []*node[C]
+	queued     []string
+	isStarted  bool
+	isStepping bool
+}
+
+// Start enters the initial states of the chart.
+func (m *Machine[C]) Start() {
+	if m.isStarted {
+		return
+	}
+
+	m.isStarted = true
+	m.active[m.chart.root.id] = true
+
+	m.isStepping = true
+	m.enterChildren(m.chart.root)
+	m.isStepping = false
+
+	m.drain()
+}
+
+// Stop exits every active state.
+func (m *Machine[C]) Stop() {
+	if !m.isStarted {
+		return
+	}
+
+	m.isStepping = true
+	m.exitChildren(m.chart.root)
+	m.isStepping = false
+
+	m.active[m.chart.root.id] = false
+	m.isStarted = false
+
+	// Anything sent while the states were exiting has nowhere to go
+	m.queued = m.queued[:0]
+}
+
+func (m *Machine[C]) IsIn(name string) bool {
+	n, ok := m.chart.byName[name]
+
+	return ok && m.active[n.id]
+}
+
+// Update takes any automatic transitions that are ready and then updates
+// every active state, with parents being updated before their children.
+func (m *Machine[C]) Update(delta float64) {
+	if !m.isStarted {
+		return
+	}
+
+	m.step("")
+	m.drain()
+
+	m.update(m.chart.root, delta)
+}
+
+// Send takes any transitions for the event and reports whether there
+// were any.
+// An event sent from inside a hook or guard is queued, so it always reports
+// false.
+func (m *Machine[C]) Send(event string) bool {
+	if !m.isStarted {
+		return false
+	}
+
+	if m.isStepping {
+		m.queued = append(m.queued, event)
+
+		return false
+	}
+
+	isTaken := m.step(event)
+	m.drain()
+
+	return isTaken
+}
+
+// drain takes the events that were queued by hooks and guards, including
+// any that those events cause to be queued in turn.
+func (m *Machine[C]) drain() {
+	for len(m.queued) > 0 && m.isStarted {
+		event := m.queued[0]
+		m.queued = m.queued[1:]
+
+		m.step(event)
+	}
+
+	m.queued = m.queued[:0]
+}
+
+func (m *Machine[C]) update(n *node[C], delta float64) {
+	if n.hooks.Update != nil {
+		n.hooks.Update(m.ctx, delta)
+	}
+
+	for _, child := range n.children {
+		if m.active[child.id] {
+			m.update(child, delta)
+		}
+	}
+}
+
+func (m *Machine[C]) collectLeaves(n *node[C]) {
+	isLeaf := true
+
+	for _, child := range n.children {
+		if m.active[child.id] {
+			isLeaf = false
+
+			m.collectLeaves(child)
+		}
+	}
+
+	if isLeaf {
+		m.leaves = append(m.leaves, n)
+	}
+}
+
+func (m *Machine[C]) step(event string) bool {
+	m.isStepping = true
+
+	defer func() {
+		m.isStepping = false
+	}()
+
+	m.leaves = m.leaves[:0]
+	m.collectLeaves(m.chart.root)
+
+	for i := range m.exited {
+		m.exited[i] = false
+	}
+
+	var isTaken bool
+
+	// Each active leaf takes at most one transition per step, with the
+	// transitions of a state taking priority over those of its ancestors
+	for _, leaf := range m.leaves {
+		// A transition taken for an earlier leaf might have exited this one,
+		// in which case it doesn't get a say even if it was entered again
+		if m.exited[leaf.id] {
+			continue
+		}
+
+	search:
+		for n := leaf; n != nil; n = n.parent {
+			for _, t := range n.transitions {
+				if t.event != event || t.guard != nil && !t.guard(m.ctx) {
+					continue
+				}
+
+				m.take(n, t.to)
+				isTaken = true
+
+				break search
+			}
+		}
+	}
+
+	return isTaken
+}
+
+func (m *Machine[C]) take(source, target *node[C]) {
+	// The transition exits everything below the nearest compound state that
+	// contains both the source and the target, so a transition to the same
+	// state or one of its children exits and re-enters it
+	ancestor := source.parent
+	for ancestor.parent != nil && (ancestor.isParallel || !target.isDescendantOf(ancestor)) {
+		ancestor = ancestor.parent
+	}
+
+	m.exitChildren(ancestor)
+
+	var path []*node[C]
+	for n := target; n != ancestor; n = n.parent {
+		path = append(path, n)
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+
+		m.enter(n)
+
+		if i == 0 {
+			m.enterChildren(n)
+
+			break
+		}
+
+		// Entering a parallel state on the way to the target means all of
+		// its other regions have to be entered as well
+		if n.isParallel {
+			for _, child := range n.children {
+				if child != path[i-1] {
+					m.enter(child)
+					m.enterChildren(child)
+				}
+			}
+		}
+	}
+}
+
+func (m *Machine[C]) enter(n *node[C]) {
+	m.active[n.id] = true
+
+	if n.hooks.Enter != nil {
+		n.hooks.Enter(m.ctx)
+	}
+}
+
+func (m *Machine[C]) enterChildren(n *node[C]) {
+	if n.isParallel {
+		for _, child := range n.children {
+			m.enter(child)
+			m.enterChildren(child)
+		}
+
+		return
+	}
+
+	if n.initial != nil {
+		m.enter(n.initial)
+		m.enterChildren(n.initial)
+	}
+}
+
+func (m *Machine[C]) exitChildren(n *node[C]) {
+	// Children are exited in the opposite order they were entered
+	for i := len(n.children) - 1; i >= 0; i-- {
+		child := n.children[i]
+		if !m.active[child.id] {
+			continue
+		}
+
+		m.exitChildren(child)
+
+		m.active[child.id] = false
+		m.exited[child.id] = true
+
+		if child.hooks.Exit != nil {
+			child.hooks.Exit(m.ctx)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package state
+
+import (
+	"slices"
+	"testing"
+)
+
+type machineLog struct {
+	entries []string
+}
+
+func (l *machineLog) hooks(name string) Hooks[*machineLog] {
+	return Hooks[*machineLog]{
+		Enter: func(ctx *machineLog) { ctx.entries = append(ctx.entries, "enter "+name) },
+		Exit:  func(ctx *machineLog) { ctx.entries = append(ctx.entries, "exit "+name) },
+	}
+}
+
+func TestMachineSendFromEnterHook(t *testing.T) {
+	var m *Machine[*machineLog]
+
+	log := &machineLog{}
+	c := NewChart[*machineLog]()
+
+	c.State("a", "", log.hooks("a"))
+	c.State("b", "", Hooks[*machineLog]{
+		Enter: func(ctx *machineLog) {
+			ctx.entries = append(ctx.entries, "enter b")
+
+			if m.Send("next") {
+				t.Error("Send from a hook reported a transition before it was taken")
+			}
+		},
+		Exit: log.hooks("b").Exit,
+	})
+	c.State("c", "", log.hooks("c"))
+	c.Initial("", "a")
+	c.On("go", "a", "b", nil)
+	c.On("next", "b", "c", nil)
+
+	m = c.New(log)
+	m.Start()
+
+	if !m.Send("go") {
+		t.Fatal("Send(go) took no transitions")
+	}
+
+	want := []string{"enter a", "exit a", "enter b", "exit b", "enter c"}
+	if !slices.Equal(log.entries, want) {
+		t.Errorf("entries = %q, want %q", log.entries, want)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		if m.IsIn(name) {
+			t.Errorf("machine is still in %q", name)
+		}
+	}
+
+	if !m.IsIn("c") {
+		t.Error("machine is not in c")
+	}
+}
+
+func TestMachineSendFromParallelEnterHook(t *testing.T) {
+	var m *Machine[*machineLog]
+
+	log := &machineLog{}
+	c := NewChart[*machineLog]()
+
+	c.Parallel("p", "", Hooks[*machineLog]{})
+	c.State("left", "p", Hooks[*machineLog]{})
+	c.State("l1", "left", log.hooks("l1"))
+	c.State("l2", "left", Hooks[*machineLog]{
+		Enter: func(ctx *machineLog) {
+			ctx.entries = append(ctx.entries, "enter l2")
+			m.Send("other")
+		},
+	})
+	c.State("right", "p", Hooks[*machineLog]{})
+	c.State("r1", "right", log.hooks("r1"))
+	c.State("r2", "right", log.hooks("r2"))
+	c.Initial("", "p")
+	c.Initial("left", "l1")
+	c.Initial("right", "r1")
+	c.On("go", "l1", "l2", nil)
+	c.On("go", "r1", "r2", nil)
+	c.On("other", "r2", "r1", nil)
+
+	m = c.New(log)
+	m.Start()
+	m.Send("go")
+
+	// Both regions take "go" before the queued "other" moves right back
+	want := []string{"enter l1", "enter r1", "exit l1", "enter l2", "exit r1", "enter r2", "exit r2", "enter r1"}
+	if !slices.Equal(log.entries, want) {
+		t.Errorf("entries = %q, want %q", log.entries, want)
+	}
+
+	if !m.IsIn("l2") || !m.IsIn("r1") {
+		t.Errorf("machine should be in l2 and r1")
+	}
+}
+
+func TestMachineSendFromStartAndStop(t *testing.T) {
+	var m *Machine[*machineLog]
+
+	log := &machineLog{}
+	c := NewChart[*machineLog]()
+
+	c.State("a", "", Hooks[*machineLog]{
+		Enter: func(ctx *machineLog) { m.Send("go") },
+	})
+	c.State("b", "", Hooks[*machineLog]{
+		Exit: func(ctx *machineLog) { m.Send("back") },
+	})
+	c.Initial("", "a")
+	c.On("go", "a", "b", nil)
+	c.On("back", "b", "a", nil)
+
+	m = c.New(log)
+	m.Start()
+
+	if !m.IsIn("b") {
+		t.Fatal("event sent while starting was not taken")
+	}
+
+	m.Stop()
+
+	if m.IsIn("a") || m.IsIn("b") {
+		t.Error("event sent while stopping re-entered a state")
+	}
+
+	if len(m.queued) != 0 {
+		t.Errorf("%d events still queued after Stop", len(m.queued))
+	}
+}
+
+func TestChartOnPanicsWithoutTarget(t *testing.T) {
+	c := NewChart[*machineLog]()
+	c.State("a", "", Hooks[*machineLog]{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("On with an empty target did not panic")
+		}
+	}()
+
+	c.On("go", "a", "", nil)
+}
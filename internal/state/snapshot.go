@@ -0,0 +1,156 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Snapshotter can be implemented by a state that has data of its own to
+// save in a Snapshot, such as the level the player is in.
+type Snapshotter interface {
+	Snapshot() (json.RawMessage, error)
+	Restore(controller Controller, ctx *Context, snapshot json.RawMessage) error
+}
+
+// Snapshot is the stack of an FSM in a form that can be saved and restored
+// later, with the bottom of the stack first.
+type Snapshot struct {
+	States []StateSnapshot `json:"states"`
+}
+
+// StateSnapshot is a state on the stack along with the message it was
+// entered with, encoded as JSON, and its own data if it's a Snapshotter.
+type StateSnapshot struct {
+	Name    string          `json:"name"`
+	Message json.RawMessage `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type messageDecoder func(data json.RawMessage) (any, error)
+
+// restoredState is a StateSnapshot with its message decoded, ready to be
+// entered.
+type restoredState struct {
+	name    string
+	message any
+	data    json.RawMessage
+}
+
+// RegisterMessage sets the type that the message a state was entered with is
+// decoded into when it's restored from a Snapshot.
+// Any state that's entered with a message needs one to be restored.
+func RegisterMessage[T any](f *FSM, name string) {
+	if _, ok := f.decoders[name]; ok {
+		panic(fmt.Sprintf("duplicate message registration for %q", name))
+	}
+
+	f.decoders[name] = func(data json.RawMessage) (any, error) {
+		var message T
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, err
+		}
+
+		return message, nil
+	}
+}
+
+// Snapshot saves the names of the states on the stack and the messages they
+// were entered with, along with the data of any of them that implement
+// Snapshotter.
+// Transitions that are still pending aren't part of the snapshot.
+func (f *FSM) Snapshot() (Snapshot, error) {
+	var snapshot Snapshot
+
+	// The base state isn't registered, so there's nothing to save for it
+	for _, e := range f.stack[1:] {
+		s := StateSnapshot{Name: e.name}
+
+		if e.message != nil {
+			message, err := json.Marshal(e.message)
+			if err != nil {
+				return Snapshot{}, fmt.Errorf("snapshot of message for state %q: %w", e.name, err)
+			}
+
+			s.Message = message
+		}
+
+		if snapshotter, ok := e.state.(Snapshotter); ok {
+			data, err := snapshotter.Snapshot()
+			if err != nil {
+				return Snapshot{}, fmt.Errorf("snapshot of state %q: %w", e.name, err)
+			}
+
+			s.Data = data
+		}
+
+		snapshot.States = append(snapshot.States, s)
+	}
+
+	return snapshot, nil
+}
+
+// Restore queues the stack to be replaced with the one in the snapshot.
+//
+// Every state on the stack is exited, and then the states in the snapshot
+// are pushed from the bottom up, being entered with the message they were
+// originally entered with and then given their data if they implement
+// Snapshotter.
+// The messages are decoded straight away, so an error is returned for a
+// message whose type wasn't registered with RegisterMessage.
+// Errors from restoring the data of a state are reported through the error
+// hooks, and the states above it are still pushed.
+func (f *FSM) Restore(snapshot Snapshot) error {
+	states := make([]restoredState, len(snapshot.States))
+
+	for i, s := range snapshot.States {
+		if _, ok := f.states[s.Name]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownState, s.Name)
+		}
+
+		states[i] = restoredState{name: s.Name, data: s.Data}
+
+		if len(s.Message) == 0 || string(s.Message) == "null" {
+			continue
+		}
+
+		decode, ok := f.decoders[s.Name]
+		if !ok {
+			return fmt.Errorf("no message type registered for state %q", s.Name)
+		}
+
+		message, err := decode(s.Message)
+		if err != nil {
+			return fmt.Errorf("restore of message for state %q: %w", s.Name, err)
+		}
+
+		states[i].message = message
+	}
+
+	f.pending = append(f.pending, pendingTransition{kind: transitionRestore, message: states})
+	f.depth = len(states) + 1
+
+	return nil
+}
+
+func (f *FSM) applyRestore(states []restoredState) {
+	for n := len(f.stack) - 1; n > 0; n-- {
+		f.exit(f.stack[n])
+
+		f.stack[n] = entry{}
+	}
+
+	f.stack = f.stack[:1]
+
+	for _, s := range states {
+		f.applyPush(s.name, s.message)
+
+		snapshotter, ok := f.states[s.name].(Snapshotter)
+		if !ok {
+			continue
+		}
+
+		if err := snapshotter.Restore(f, f.stack[len(f.stack)-1].ctx, s.data); err != nil {
+			f.error(fmt.Errorf("restore of state %q: %w", s.name, err))
+		}
+	}
+}
@@ -1,36 +1,62 @@
 package state
 
-import "github.com/robotscone/adventure/internal/input"
-
 type Controller interface {
-	Switch(name string, message any)
-	Push(name string, message any)
-	Pop()
-}
-
-type Data struct {
-	Device *input.Device
-	Delta  float64
+	Switch(name string, message any) error
+	Push(name string, message any) error
+	Pop() error
 }
 
 type State interface {
-	Init(controller Controller, data *Data)
-	Enter(controller Controller, data *Data, message any)
-	Resume(controller Controller, data *Data)
-	Input(controller Controller, data *Data)
-	Update(controller Controller, data *Data)
+	Init(controller Controller, ctx *Context)
+	Enter(controller Controller, ctx *Context, message any)
+	Resume(controller Controller, ctx *Context)
+	Input(controller Controller, ctx *Context)
+	Update(controller Controller, ctx *Context)
 	Draw()
 	Pause()
 	Exit()
 }
 
+// Policy describes how a state affects the states beneath it on the stack.
+//
+// A transparent state lets the states beneath it be drawn, a state that
+// blocks updates stops the states beneath it from being updated, such as a
+// pause menu, and a state that captures input stops the states beneath it
+// from getting any input.
+type Policy struct {
+	IsTransparent     bool
+	IsBlockingUpdates bool
+	IsCapturingInput  bool
+}
+
+// DefaultPolicy is used for any state that doesn't implement Overlay.
+var DefaultPolicy = Policy{
+	IsTransparent:     true,
+	IsBlockingUpdates: true,
+	IsCapturingInput:  true,
+}
+
+// Overlay can be implemented by a state to change its Policy, for example to
+// let the world carry on running beneath a dialogue box.
+type Overlay interface {
+	Policy() Policy
+}
+
+func policyOf(state State) Policy {
+	if overlay, ok := state.(Overlay); ok {
+		return overlay.Policy()
+	}
+
+	return DefaultPolicy
+}
+
 type Base struct{}
 
-func (*Base) Init(controller Controller, data *Data)               {}
-func (*Base) Enter(controller Controller, data *Data, message any) {}
-func (*Base) Resume(controller Controller, data *Data)             {}
-func (*Base) Input(controller Controller, data *Data)              {}
-func (*Base) Update(controller Controller, data *Data)             {}
-func (*Base) Draw()                                                {}
-func (*Base) Pause()                                               {}
-func (*Base) Exit()                                                {}
+func (*Base) Init(controller Controller, ctx *Context)               {}
+func (*Base) Enter(controller Controller, ctx *Context, message any) {}
+func (*Base) Resume(controller Controller, ctx *Context)             {}
+func (*Base) Input(controller Controller, ctx *Context)              {}
+func (*Base) Update(controller Controller, ctx *Context)             {}
+func (*Base) Draw()                                                  {}
+func (*Base) Pause()                                                 {}
+func (*Base) Exit()                                                  {}
@@ -1,89 +1,350 @@
 package timer
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 type Func func()
 
-type task struct {
-	elapsed  float64
-	interval float64
-	limit    int
-	runs     int
-	f        Func
+// Handle is returned when a function is scheduled so that it can be changed
+// or cancelled later.
+type Handle struct {
+	timer       *Timer
+	elapsed     float64
+	interval    float64
+	limit       int
+	runs        int
+	f           Func
+	tags        []string
+	isEvery     bool
+	isPaused    bool
+	isCancelled bool
+	isDone      bool
+	isScheduled bool
+}
+
+// Cancel stops the function from being called again.
+func (h *Handle) Cancel() {
+	h.isCancelled = true
+}
+
+func (h *Handle) Pause() {
+	h.isPaused = true
+}
+
+func (h *Handle) Resume() {
+	h.isPaused = false
+}
+
+func (h *Handle) IsPaused() bool {
+	return h.isPaused
+}
+
+// IsActive reports whether the function is still waiting to be called,
+// even if it's paused.
+func (h *Handle) IsActive() bool {
+	return !h.isCancelled && !h.isDone
+}
+
+// Remaining is how long is left until the function is next called, which is
+// zero once it's been cancelled or won't be called again.
+func (h *Handle) Remaining() time.Duration {
+	if !h.IsActive() {
+		return 0
+	}
+
+	return time.Duration((h.interval - h.elapsed) * float64(time.Second))
+}
+
+// Reschedule changes the interval and starts counting down from the
+// beginning again, without changing how many times it's already run.
+// A handle that has already finished or been cancelled is scheduled again,
+// and starts counting its runs from zero.
+func (h *Handle) Reschedule(interval time.Duration) {
+	h.interval = interval.Seconds()
+	h.elapsed = 0
+
+	if h.IsActive() {
+		return
+	}
+
+	h.runs = 0
+	h.isCancelled = false
+	h.isDone = false
+
+	// A handle that finished during the current update hasn't been removed
+	// yet, so it only needs adding back once it's gone
+	if !h.isScheduled {
+		h.timer.schedule(h)
+	}
+}
+
+// Tag adds the handle to groups that can be cancelled, paused and resumed
+// together, such as everything that belongs to an entity.
+func (h *Handle) Tag(tags ...string) *Handle {
+	h.tags = append(h.tags, tags...)
+
+	return h
+}
+
+func (h *Handle) hasTag(tag string) bool {
+	for _, t := range h.tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Timer struct {
-	afters []*task
-	everys []*task
+	afters     []*Handle
+	everys     []*Handle
+	scale      float64
+	maxCatchUp int
+	isPaused   bool
+	isUpdating bool
 }
 
 func New() *Timer {
-	return &Timer{}
+	return &Timer{scale: 1}
 }
 
-func (t *Timer) After(interval time.Duration, f Func) {
-	t.afters = append(t.afters, &task{
+func (t *Timer) After(interval time.Duration, f Func) *Handle {
+	handle := &Handle{
+		timer:    t,
 		interval: interval.Seconds(),
 		f:        f,
-	})
+	}
+
+	t.schedule(handle)
+
+	return handle
 }
 
-func (t *Timer) Every(interval time.Duration, limit int, f Func) {
-	t.everys = append(t.everys, &task{
+func (t *Timer) Every(interval time.Duration, limit int, f Func) *Handle {
+	handle := &Handle{
+		timer:    t,
 		interval: interval.Seconds(),
 		limit:    limit,
 		f:        f,
-	})
+		isEvery:  true,
+	}
+
+	t.schedule(handle)
+
+	return handle
 }
 
-func (t *Timer) Clear() {
-	t.afters = make([]*task, 0)
-	t.everys = make([]*task, 0)
+func (t *Timer) schedule(handle *Handle) {
+	handle.isScheduled = true
+
+	if handle.isEvery {
+		t.everys = append(t.everys, handle)
+	} else {
+		t.afters = append(t.afters, handle)
+	}
 }
 
-func (t *Timer) Reset() {
-	for _, task := range t.afters {
-		task.elapsed = 0
-		task.runs = 0
+func (t *Timer) Pause() {
+	t.isPaused = true
+}
+
+func (t *Timer) Resume() {
+	t.isPaused = false
+}
+
+func (t *Timer) IsPaused() bool {
+	return t.isPaused
+}
+
+// SetScale sets how fast time passes for the timer, where 0.5 is half speed
+// for slow motion and 2 is double speed.
+func (t *Timer) SetScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+
+	t.scale = scale
+}
+
+func (t *Timer) Scale() float64 {
+	return t.scale
+}
+
+// SetMaxCatchUp sets the most times a function scheduled with Every can be
+// called in a single update, which stops a hitch from causing a burst of
+// calls to catch up on the intervals that were missed.
+// Anything less than 1 means there's no limit, which is the default.
+func (t *Timer) SetMaxCatchUp(calls int) {
+	t.maxCatchUp = calls
+}
+
+func (t *Timer) each(f func(handle *Handle)) {
+	for _, handle := range t.afters {
+		f(handle)
 	}
 
-	for _, task := range t.everys {
-		task.elapsed = 0
-		task.runs = 0
+	for _, handle := range t.everys {
+		f(handle)
 	}
 }
 
+func (t *Timer) CancelTag(tag string) {
+	t.each(func(handle *Handle) {
+		if handle.hasTag(tag) {
+			handle.Cancel()
+		}
+	})
+}
+
+func (t *Timer) PauseTag(tag string) {
+	t.each(func(handle *Handle) {
+		if handle.hasTag(tag) {
+			handle.Pause()
+		}
+	})
+}
+
+func (t *Timer) ResumeTag(tag string) {
+	t.each(func(handle *Handle) {
+		if handle.hasTag(tag) {
+			handle.Resume()
+		}
+	})
+}
+
+func (t *Timer) Clear() {
+	t.each((*Handle).Cancel)
+
+	// Clearing from inside a callback leaves the cancelled handles where they
+	// are for Update to remove once it's finished with them
+	if t.isUpdating {
+		return
+	}
+
+	t.each(func(handle *Handle) {
+		handle.isScheduled = false
+	})
+
+	t.afters = make([]*Handle, 0)
+	t.everys = make([]*Handle, 0)
+}
+
+func (t *Timer) Reset() {
+	t.each(func(handle *Handle) {
+		handle.elapsed = 0
+		handle.runs = 0
+	})
+}
+
+// Update moves the timer on and calls any functions that are due.
+//
+// Functions can schedule, cancel and clear from inside their callbacks.
+// Anything they schedule isn't updated until the next call to Update, and
+// anything they cancel isn't called again, even if it was due this update.
 func (t *Timer) Update(delta float64) {
-	afters := t.afters[:0]
-	for _, task := range t.afters {
-		task.elapsed += delta
+	// A callback calling Update would move everything on twice, so it's
+	// ignored instead
+	if t.isPaused || t.isUpdating {
+		return
+	}
 
-		if task.elapsed > task.interval {
-			task.f()
-		} else {
-			afters = append(afters, task)
+	t.isUpdating = true
+
+	delta *= t.scale
+
+	// Anything scheduled by a callback is appended after n, so we only go as
+	// far as what was there when we started
+	for i, n := 0, len(t.afters); i < n; i++ {
+		handle := t.afters[i]
+		if !handle.IsActive() || handle.isPaused {
+			continue
+		}
+
+		handle.elapsed += delta
+
+		if handle.elapsed > handle.interval {
+			handle.isDone = true
+			handle.f()
+		}
+	}
+
+	for i, n := 0, len(t.everys); i < n; i++ {
+		handle := t.everys[i]
+		if !handle.IsActive() || handle.isPaused {
+			continue
 		}
+
+		handle.elapsed += delta
+
+		t.run(handle)
 	}
-	t.afters = afters
 
-	everys := t.everys[:0]
-	for _, task := range t.everys {
-		task.elapsed += delta
+	t.isUpdating = false
 
-		for task.elapsed >= task.interval {
-			if task.limit > 0 && task.runs >= task.limit {
-				break
+	// Nothing is removed until all of the callbacks have run, otherwise we'd
+	// be compacting the same slices that the callbacks are appending to
+	t.afters = compact(t.afters)
+	t.everys = compact(t.everys)
+}
+
+func (t *Timer) run(handle *Handle) {
+	maxCalls := t.maxCatchUp
+
+	// An interval of zero would never stop catching up, so it's treated as
+	// once per update
+	if handle.interval <= 0 {
+		maxCalls = 1
+	}
+
+	for calls := 0; handle.elapsed >= handle.interval; calls++ {
+		// We drop the intervals that were missed while keeping whatever was
+		// left over from the last one, so it stays in time with where it
+		// would have been without the hitch
+		if maxCalls > 0 && calls >= maxCalls {
+			if handle.interval > 0 {
+				handle.elapsed = math.Mod(handle.elapsed, handle.interval)
+			} else {
+				handle.elapsed = 0
 			}
 
-			task.runs++
-			task.f()
+			return
+		}
+
+		// The elapsed time is moved on before the call so that the function
+		// can reschedule itself
+		handle.runs++
+		handle.elapsed -= handle.interval
+
+		handle.f()
 
-			task.elapsed -= task.interval
+		if handle.limit > 0 && handle.runs >= handle.limit {
+			handle.isDone = true
+
+			return
 		}
 
-		if task.limit <= 0 || task.runs < task.limit {
-			everys = append(everys, task)
+		if !handle.IsActive() || handle.isPaused {
+			return
 		}
 	}
-	t.everys = everys
+}
+
+func compact(handles []*Handle) []*Handle {
+	active := handles[:0]
+	for _, handle := range handles {
+		if handle.IsActive() {
+			active = append(active, handle)
+		} else {
+			handle.isScheduled = false
+		}
+	}
+
+	for i := len(active); i < len(handles); i++ {
+		handles[i] = nil
+	}
+
+	return active
 }